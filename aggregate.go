@@ -0,0 +1,159 @@
+package radixtree
+
+import (
+	"iter"
+	"strings"
+)
+
+// Monoid defines how the values of an AggregateTree are aggregated. FromValue
+// returns the aggregate of a single value, and Combine returns the aggregate of
+// two adjacent groups of values, given their aggregates in key order. Combine
+// must be associative, but need not be commutative.
+type Monoid[T, A any] interface {
+	FromValue(value T) A
+	Combine(a, b A) A
+}
+
+// AggregateTree is a radix tree that keeps, for each node, an aggregate of the
+// values in the node's subtree, such as a sum, minimum, or histogram, defined
+// by a Monoid. The aggregates are held by the AggregateTree, not by the nodes,
+// so other trees do not pay for them. On each Put and Delete, the aggregate of
+// each node along the modified path is recomputed by combining the aggregates
+// of its children, so the cost is proportional to the key length and the
+// number of children of the nodes along the path.
+//
+// The aggregate of all values with a prefix is read from the node at the
+// prefix, in O(prefix-length). The aggregate of a range of keys combines the
+// aggregates of the children of the nodes along the paths to the ends of the
+// range.
+type AggregateTree[T, A any] struct {
+	tree Tree[T]
+	aggs subtreeAggs[T, A]
+}
+
+// NewAggregateTree creates a new radix tree that aggregates its values using
+// the given monoid.
+func NewAggregateTree[T, A any](m Monoid[T, A]) *AggregateTree[T, A] {
+	return &AggregateTree[T, A]{
+		aggs: subtreeAggs[T, A]{
+			fromItem: func(item *Item[T]) A { return m.FromValue(item.value) },
+			combine:  m.Combine,
+		},
+	}
+}
+
+// Len returns the number of values stored in the tree.
+func (t *AggregateTree[T, A]) Len() int {
+	return t.tree.Len()
+}
+
+// Get returns the value stored at the given key. Returns false if there is no
+// value present for the key.
+func (t *AggregateTree[T, A]) Get(key string) (T, bool) {
+	return t.tree.Get(key)
+}
+
+// Put inserts the value into the tree at the given key, replacing any existing
+// value. It returns true if it adds a new value, false if it replaces an
+// existing value.
+func (t *AggregateTree[T, A]) Put(key string, value T) bool {
+	isNew := t.tree.Put(key, value)
+	t.aggs.update(&t.tree, key)
+	return isNew
+}
+
+// Delete removes the value associated with the given key. Returns true if
+// there was a value stored for the key.
+func (t *AggregateTree[T, A]) Delete(key string) bool {
+	t.aggs.forget(&t.tree, key, false)
+	ok := t.tree.Delete(key)
+	t.aggs.update(&t.tree, key)
+	return ok
+}
+
+// DeletePrefix removes all values whose key is prefixed by the given prefix.
+// Returns true if any values were removed.
+func (t *AggregateTree[T, A]) DeletePrefix(prefix string) bool {
+	t.aggs.forget(&t.tree, prefix, true)
+	ok := t.tree.DeletePrefix(prefix)
+	t.aggs.update(&t.tree, prefix)
+	return ok
+}
+
+// Iter visits all values in the tree, yielding the key and value of each.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *AggregateTree[T, A]) Iter() iter.Seq2[string, T] {
+	return t.tree.Iter()
+}
+
+// IterAt visits all values whose keys match or are prefixed by the specified
+// key, yielding the key and value of each.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *AggregateTree[T, A]) IterAt(key string) iter.Seq2[string, T] {
+	return t.tree.IterAt(key)
+}
+
+// AggregatePrefix returns the aggregate of all values whose keys match or are
+// prefixed by the given prefix. An empty prefix aggregates all values in the
+// tree. Returns false if there are no such values.
+func (t *AggregateTree[T, A]) AggregatePrefix(prefix string) (A, bool) {
+	node, _ := t.tree.locate(prefix)
+	if node == nil {
+		var zero A
+		return zero, false
+	}
+	return t.aggs.get(node)
+}
+
+// AggregateRange returns the aggregate of all values whose keys are greater
+// than or equal to start and less than end. An empty end places no upper bound
+// on the keys. Returns false if there are no such values.
+//
+// Only the nodes along the paths to start and end are visited, and the stored
+// aggregates of the subtrees between them are combined.
+func (t *AggregateTree[T, A]) AggregateRange(start, end string) (A, bool) {
+	return t.aggs.aggRange(&t.tree.root, 0, start, end)
+}
+
+// aggRange returns the aggregate of the items in the node's subtree with keys
+// in the range [start, end), where the node's key is depth bytes long.
+func (s *subtreeAggs[T, A]) aggRange(node *radixNode[T], depth int, start, end string) (A, bool) {
+	var (
+		a  A
+		ok bool
+	)
+	if node.leaf == nil && len(node.nodes) == 0 {
+		return a, false
+	}
+	key := node.firstItem().key[:depth]
+	if key < start && !strings.HasPrefix(start, key) {
+		// All keys are before start.
+		return a, false
+	}
+	if end != "" && key >= end {
+		// All keys are at or after end.
+		return a, false
+	}
+	if key >= start && (end == "" || !strings.HasPrefix(end, key)) {
+		// All keys are in the range.
+		return s.get(node)
+	}
+
+	if node.leaf != nil && node.leaf.key >= start && (end == "" || node.leaf.key < end) {
+		a, ok = s.fromItem(node.leaf), true
+	}
+	for _, child := range node.nodes {
+		ca, cok := s.aggRange(child, depth+1+len(child.prefix), start, end)
+		if !cok {
+			continue
+		}
+		if ok {
+			a = s.combine(a, ca)
+		} else {
+			a, ok = ca, true
+		}
+	}
+	return a, ok
+}
@@ -0,0 +1,165 @@
+package radixtree
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type sumMonoid struct{}
+
+func (sumMonoid) FromValue(v int) int  { return v }
+func (sumMonoid) Combine(a, b int) int { return a + b }
+
+// concatMonoid is not commutative, to check that values are combined in key
+// order.
+type concatMonoid struct{}
+
+func (concatMonoid) FromValue(v string) string  { return v }
+func (concatMonoid) Combine(a, b string) string { return a + b }
+
+func TestAggregatePrefix(t *testing.T) {
+	at := NewAggregateTree[int, int](sumMonoid{})
+	at.Put("usage/a/x", 1)
+	at.Put("usage/a/y", 2)
+	at.Put("usage/b", 4)
+	at.Put("usage", 8)
+	at.Put("other", 16)
+
+	for prefix, want := range map[string]int{
+		"":        31,
+		"usage":   15,
+		"usage/":  7,
+		"usage/a": 3,
+		"usa":     15,
+		"o":       16,
+	} {
+		if sum, ok := at.AggregatePrefix(prefix); !ok || sum != want {
+			t.Fatalf("AggregatePrefix(%q): expected %d, got %d %t", prefix, want, sum, ok)
+		}
+	}
+	if _, ok := at.AggregatePrefix("x"); ok {
+		t.Fatal("expected no aggregate for missing prefix")
+	}
+
+	at.Put("usage/a/x", 100)
+	at.Delete("usage/b")
+	if sum, _ := at.AggregatePrefix("usage"); sum != 110 {
+		t.Fatalf("expected 110, got %d", sum)
+	}
+	at.DeletePrefix("usage/")
+	if sum, _ := at.AggregatePrefix(""); sum != 24 {
+		t.Fatalf("expected 24, got %d", sum)
+	}
+	at.DeletePrefix("")
+	if _, ok := at.AggregatePrefix(""); ok {
+		t.Fatal("expected no aggregate for empty tree")
+	}
+}
+
+func TestAggregateRange(t *testing.T) {
+	at := NewAggregateTree[string, string](concatMonoid{})
+	ref := map[string]string{}
+	rnd := rand.New(rand.NewSource(1))
+	randKey := func() string {
+		return strconv.FormatInt(rnd.Int63n(3000), 3)
+	}
+
+	check := func(start, end string) {
+		var want string
+		var wantOK bool
+		for key, val := range at.Iter() {
+			if key >= start && (end == "" || key < end) {
+				want += val
+				wantOK = true
+			}
+		}
+		got, ok := at.AggregateRange(start, end)
+		if ok != wantOK || got != want {
+			t.Fatalf("AggregateRange(%q, %q): expected %q %t, got %q %t", start, end, want, wantOK, got, ok)
+		}
+
+		want, wantOK = "", false
+		for _, val := range at.IterAt(start) {
+			want += val
+			wantOK = true
+		}
+		got, ok = at.AggregatePrefix(start)
+		if ok != wantOK || got != want {
+			t.Fatalf("AggregatePrefix(%q): expected %q %t, got %q %t", start, want, wantOK, got, ok)
+		}
+	}
+
+	for i := range 10000 {
+		key := randKey()
+		switch rnd.Intn(10) {
+		case 0, 1, 2:
+			at.Delete(key)
+			delete(ref, key)
+		case 3:
+			key = key[:len(key)/2]
+			at.DeletePrefix(key)
+			for k := range ref {
+				if strings.HasPrefix(k, key) {
+					delete(ref, k)
+				}
+			}
+		default:
+			val := strconv.Itoa(rnd.Intn(10))
+			at.Put(key, val)
+			ref[key] = val
+		}
+		if at.Len() != len(ref) {
+			t.Fatalf("expected size %d, got %d", len(ref), at.Len())
+		}
+		if i%1000 == 0 {
+			checkAggs(t, &at.tree, &at.aggs)
+		}
+		if i%50 == 0 {
+			start, end := randKey(), randKey()
+			start = start[:rnd.Intn(len(start)+1)]
+			if rnd.Intn(4) == 0 {
+				end = ""
+			}
+			check(start, end)
+		}
+	}
+}
+
+func TestAggregateDeletePrefix(t *testing.T) {
+	at := NewAggregateTree[int, int](sumMonoid{})
+	for i := range 20000 {
+		at.Put("a"+strconv.Itoa(i), 1)
+		at.Put("b"+strconv.Itoa(i), 2)
+	}
+	if sum, _ := at.AggregatePrefix(""); sum != 60000 {
+		t.Fatalf("expected 60000, got %d", sum)
+	}
+	at.DeletePrefix("b")
+	if sum, _ := at.AggregatePrefix(""); sum != 20000 {
+		t.Fatalf("expected 20000, got %d", sum)
+	}
+	if _, ok := at.AggregatePrefix("b"); ok {
+		t.Fatal("expected no aggregate for deleted prefix")
+	}
+	// The deleted subtree is unlinked, and the aggregates of the remaining
+	// nodes are correct.
+	if at.tree.root.getEdge('b') != nil {
+		t.Fatal("expected deleted subtree to be unlinked")
+	}
+	checkAggs(t, &at.tree, &at.aggs)
+
+	for i := range 20000 {
+		at.Delete("a" + strconv.Itoa(i))
+	}
+	if _, ok := at.AggregatePrefix(""); ok || len(at.aggs.aggs) != 0 {
+		t.Fatal("expected no aggregate for empty tree")
+	}
+	if at.DeletePrefix("") || at.Len() != 0 {
+		t.Fatalf("DeletePrefix on emptied tree removed values, length %d", at.Len())
+	}
+	if at = NewAggregateTree[int, int](sumMonoid{}); at.DeletePrefix("") || at.Len() != 0 {
+		t.Fatalf("DeletePrefix on empty tree removed values, length %d", at.Len())
+	}
+}
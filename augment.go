@@ -0,0 +1,131 @@
+package radixtree
+
+import "strings"
+
+// subtreeAggs maintains an aggregate of the items in the subtree of each node
+// of a tree. The aggregates are kept in a map owned by the augmented tree, so
+// that the nodes of other trees carry no extra field.
+//
+// After each modification of the tree, update must be called with the modified
+// key so that the aggregates of the nodes along its path are recomputed. Before
+// each deletion, forget must be called with the deleted key, so that no
+// aggregates are kept for the nodes that the deletion removes.
+//
+// Nodes created by the tree, such as when a node is split, have no aggregate
+// until it is computed from their children.
+type subtreeAggs[T, A any] struct {
+	fromItem func(*Item[T]) A
+	combine  func(A, A) A
+	aggs     map[*radixNode[T]]A
+}
+
+// get returns the aggregate for the node's subtree, computing it if the node
+// has none. Returns false if the subtree has no items, which is only the case
+// for the root of an empty tree.
+func (s *subtreeAggs[T, A]) get(node *radixNode[T]) (A, bool) {
+	if a, ok := s.aggs[node]; ok {
+		return a, true
+	}
+	return s.compute(node)
+}
+
+// compute computes and stores the aggregate for the node's subtree from its
+// item and the aggregates of its children.
+func (s *subtreeAggs[T, A]) compute(node *radixNode[T]) (A, bool) {
+	var (
+		a  A
+		ok bool
+	)
+	if node.leaf != nil {
+		a, ok = s.fromItem(node.leaf), true
+	}
+	for _, child := range node.nodes {
+		ca, cok := s.get(child)
+		if !cok {
+			continue
+		}
+		if ok {
+			a = s.combine(a, ca)
+		} else {
+			a, ok = ca, true
+		}
+	}
+	if !ok {
+		delete(s.aggs, node)
+		return a, false
+	}
+	if s.aggs == nil {
+		s.aggs = make(map[*radixNode[T]]A)
+	}
+	s.aggs[node] = a
+	return a, true
+}
+
+// update recomputes the aggregates of the nodes along the path to key, from the
+// bottom up, after the tree has been modified at or below key. Each node on the
+// path combines the aggregates of all of its children.
+func (s *subtreeAggs[T, A]) update(t *Tree[T], key string) {
+	var pathArr [64]*radixNode[T]
+	path := aggPath(t, key, pathArr[:0])
+	for i := len(path) - 1; i >= 0; i-- {
+		s.compute(path[i])
+	}
+}
+
+// forget removes the aggregates of the nodes that may be removed by deleting
+// key, or all keys prefixed by key if prefix is true, before the deletion.
+//
+// A deletion removes nodes along the path to key, and may compress a node on
+// the path with its only remaining child, removing the child. Such a node has
+// at most two children before the deletion, so the aggregates of the children
+// of those nodes are removed. A prefix deletion also removes the subtree at the
+// prefix. Aggregates removed for nodes that remain in the tree are recomputed
+// when they are next needed.
+func (s *subtreeAggs[T, A]) forget(t *Tree[T], key string, prefix bool) {
+	if len(s.aggs) == 0 {
+		return
+	}
+	var pathArr [64]*radixNode[T]
+	for _, node := range aggPath(t, key, pathArr[:0]) {
+		delete(s.aggs, node)
+		if len(node.nodes) <= 2 {
+			for _, child := range node.nodes {
+				delete(s.aggs, child)
+			}
+		}
+	}
+	if prefix {
+		if node, _ := t.locate(key); node != nil {
+			s.forgetSubtree(node)
+		}
+	}
+}
+
+// forgetSubtree removes the aggregates of the node and all of its descendants.
+func (s *subtreeAggs[T, A]) forgetSubtree(node *radixNode[T]) {
+	delete(s.aggs, node)
+	for _, child := range node.nodes {
+		s.forgetSubtree(child)
+	}
+}
+
+// aggPath appends to path the nodes along the path to key, from the root down
+// to the node at key, or to the last node that the key reaches.
+func aggPath[T any](t *Tree[T], key string, path []*radixNode[T]) []*radixNode[T] {
+	node := &t.root
+	path = append(path, node)
+	for len(key) != 0 {
+		if node = node.getEdge(key[0]); node == nil {
+			break
+		}
+		path = append(path, node)
+
+		// Consume key data.
+		key = key[1:]
+		if !strings.HasPrefix(key, node.prefix) {
+			break
+		}
+		key = key[len(node.prefix):]
+	}
+	return path
+}
@@ -0,0 +1,62 @@
+package radixtree
+
+import (
+	"iter"
+)
+
+// Automaton is a deterministic finite automaton that consumes keys one byte
+// at a time. States are identified by integers chosen by the implementation.
+type Automaton interface {
+	// Start returns the initial state.
+	Start() int
+	// Step returns the state reached by consuming b in the given state.
+	Step(state int, b byte) int
+	// IsMatch reports whether the key consumed to reach the state is
+	// accepted.
+	IsMatch(state int) bool
+	// CanMatch reports whether any key, continuing from the state, may be
+	// accepted. Returning false stops the traversal of a subtree.
+	CanMatch(state int) bool
+}
+
+// IterAutomaton visits all nodes whose keys are accepted by the automaton,
+// yielding the key and value of each.
+//
+// The tree is walked once, stepping the automaton along each edge. Subtrees
+// are skipped as soon as the automaton reports that no match is possible, so
+// a selective automaton visits only a small part of the tree.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *Tree[T]) IterAutomaton(a Automaton) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		state := a.Start()
+		if a.CanMatch(state) {
+			t.root.walkAutomaton(a, state, checkedYield(t, yield))
+		}
+	}
+}
+
+// walkAutomaton visits the node, whose edge radix has been consumed to arrive
+// at the given state, and its children. Returns false if iteration was
+// stopped.
+func (node *radixNode[T]) walkAutomaton(a Automaton, state int, yield func(string, T) bool) bool {
+	for i := 0; i < len(node.prefix); i++ {
+		state = a.Step(state, node.prefix[i])
+		if !a.CanMatch(state) {
+			return true
+		}
+	}
+	if node.leaf != nil && a.IsMatch(state) && !yield(node.leaf.key, node.leaf.value) {
+		return false
+	}
+	for i, child := range node.nodes {
+		next := a.Step(state, node.radices[i])
+		if !a.CanMatch(next) {
+			continue
+		}
+		if !child.walkAutomaton(a, next, yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package radixtree
+
+import (
+	"math/rand"
+	"regexp"
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIterAutomaton(t *testing.T) {
+	keys := []string{
+		"", "a", "ab", "abc", "abcd", "b", "ba", "bab", "tom", "tomato",
+		"tommy", "torn", "tornado", "user/42/posts", "user/7/posts",
+		"user/x/posts", "user/42", "AbC", "a\nb", "é", "É", "café", "\xe9",
+		"\u212a", "ſk", "日本", "\U0001f600",
+	}
+	rt := New[int]()
+	for i, k := range keys {
+		rt.Put(k, i)
+	}
+
+	exprs := []string{
+		"", "a", "ab*", "(ab)+", "a.*", "to(m|rn)(ato|ado|my)?", "[ab]+",
+		"user/[0-9]+/posts", "user/\\d+(/posts)?", "(?i)abc", "a.b", "(?s)a.b",
+		"^tom$", "^$", "[^t]*", "x|y", ".*o", "a{2,3}", "$^", "(?i)[a-z]",
+		"(?i)[a-z]+", "(?i)[k-m]+o[^a]*", "[a-c\u0100-\u0200]+", "[\u0100-\u0200]",
+		"é", "[éa]", "(?i)é", "caf[é]", "caf.+", "[^x]*é", "\\pL+", "(?i)k", "(?i)sk",
+		"(?i)[s-t]+k", "[\u00e0-\u00ff]", "\\p{Han}+",
+	}
+	for _, expr := range exprs {
+		a, err := CompileRegexp(expr)
+		if err != nil {
+			t.Fatalf("cannot compile %q: %s", expr, err)
+		}
+		re := regexp.MustCompile("^(?:" + expr + ")$")
+		var expect, found []string
+		for _, k := range keys {
+			// Invalid UTF-8 is never matched, unlike in the regexp package.
+			if utf8.ValidString(k) && re.MatchString(k) {
+				expect = append(expect, k)
+			}
+		}
+		slices.Sort(expect)
+		for k, v := range rt.IterAutomaton(a) {
+			if keys[v] != k {
+				t.Fatalf("wrong value %d for key %q", v, k)
+			}
+			found = append(found, k)
+		}
+		if !slices.Equal(found, expect) {
+			t.Errorf("expr %q: expected %q, got %q", expr, expect, found)
+		}
+	}
+
+	a, err := CompileRegexp("tom.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for range rt.IterAutomaton(a) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+
+	for _, expr := range []string{"(?m)^a", "\\ba", "(", "a{1001}"} {
+		if _, err = CompileRegexp(expr); err == nil {
+			t.Errorf("expected error compiling %q", expr)
+		}
+	}
+}
+
+func TestRegexpAutomatonUTF8(t *testing.T) {
+	match := func(a *RegexpAutomaton, key string) bool {
+		s := a.Start()
+		for i := 0; i < len(key); i++ {
+			s = a.Step(s, key[i])
+		}
+		return a.IsMatch(s)
+	}
+	for _, tc := range []struct {
+		expr, key string
+		want      bool
+	}{
+		{"a.c", "aéc", true},
+		{"a.c", "a\xc3c", false},
+		{"a..c", "aéc", false},
+		{"a[^b]c", "aéc", true},
+		{"a[^b]c", "a\xc3c", false},
+		{"(?s).", "\U0010ffff", true},
+		{".", "\n", false},
+		{"[^é]", "\xc3", false},
+	} {
+		a, err := CompileRegexp(tc.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match(a, tc.key) != tc.want {
+			t.Errorf("expr %q, key %q: expected match %v", tc.expr, tc.key, tc.want)
+		}
+	}
+
+	// Compare with the regexp package on random multi-byte keys.
+	runes := []rune{'a', 'b', '\n', 'é', 'É', 'ß', '\u0800', '\u212a', '日', '\ufffd', '\U0001f600', '\U0010ffff'}
+	rnd := rand.New(rand.NewSource(1))
+	keys := make([]string, 2000)
+	for i := range keys {
+		r := make([]rune, rnd.Intn(5))
+		for j := range r {
+			r[j] = runes[rnd.Intn(len(runes))]
+		}
+		keys[i] = string(r)
+	}
+	exprs := []string{
+		".", "..", ".+", "(?s).*", "a.b", "[^é]+", "[^a]", "[^\\n]*日",
+		"(?i)[^k]+", "\\PL", "[\u0800-\U0010ffff]+", "(?i)é.", "[^\u0100-\uffff]*",
+	}
+	for _, expr := range exprs {
+		a, err := CompileRegexp(expr)
+		if err != nil {
+			t.Fatalf("cannot compile %q: %s", expr, err)
+		}
+		re := regexp.MustCompile("^(?:" + expr + ")$")
+		for _, k := range keys {
+			if got, want := match(a, k), re.MatchString(k); got != want {
+				t.Fatalf("expr %q, key %q: expected match %v, got %v", expr, k, want, got)
+			}
+		}
+	}
+}
+
+func TestRegexpAutomatonPrune(t *testing.T) {
+	a, err := CompileRegexp("abc|abd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := a.Start()
+	if !a.CanMatch(s) || a.IsMatch(s) {
+		t.Fatal("start state should be live and not matching")
+	}
+	s = a.Step(s, 'a')
+	s = a.Step(s, 'b')
+	if !a.CanMatch(s) {
+		t.Fatal("state after \"ab\" should be live")
+	}
+	if a.CanMatch(a.Step(s, 'x')) {
+		t.Fatal("state after \"abx\" should be dead")
+	}
+	s = a.Step(s, 'd')
+	if !a.IsMatch(s) {
+		t.Fatal("state after \"abd\" should match")
+	}
+	if a.CanMatch(a.Step(s, 'd')) {
+		t.Fatal("state after \"abdd\" should be dead")
+	}
+}
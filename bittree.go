@@ -0,0 +1,134 @@
+package radixtree
+
+import (
+	"iter"
+	"strings"
+)
+
+// BitTree is a radix tree whose keys are sequences of bits, such as binary
+// hashes and geospatial bit codes, where a prefix may end part way through a
+// byte. The tree branches on single bits, and compresses paths without
+// branches in the same way as Tree.
+//
+// A key is given as a byte slice and the number of leading bits of the slice
+// that are part of the key. Bits are taken from the most significant bit of
+// each byte first, and bits of the slice beyond the key length are ignored.
+type BitTree[T any] struct {
+	trie bitTrie[T]
+}
+
+// BitKey is a key stored in a BitTree.
+type BitKey struct {
+	key string
+	n   int
+}
+
+// NewBitTree creates a new bit-granular radix tree.
+func NewBitTree[T any]() *BitTree[T] {
+	return new(BitTree[T])
+}
+
+// Len returns the number of bits in the key.
+func (k BitKey) Len() int { return k.n }
+
+// Bit returns bit i of the key, either 0 or 1.
+func (k BitKey) Bit(i int) int {
+	if i < 0 || i >= k.n {
+		panic("radixtree: bit index out of range")
+	}
+	return bitAt(k.key, i)
+}
+
+// Bytes returns a new slice containing the bits of the key. Unused bits of
+// the last byte are zero.
+func (k BitKey) Bytes() []byte {
+	return []byte(k.key)
+}
+
+// String returns the key as a string of '0' and '1' characters.
+func (k BitKey) String() string {
+	var b strings.Builder
+	b.Grow(k.n)
+	for i := range k.n {
+		b.WriteByte('0' + byte(bitAt(k.key, i)))
+	}
+	return b.String()
+}
+
+// Len returns the number of values stored in the tree.
+func (t *BitTree[T]) Len() int {
+	return t.trie.size
+}
+
+// Get returns the value stored at the key formed by the first n bits of key.
+// Returns false if there is no value present for the key.
+func (t *BitTree[T]) Get(key []byte, n int) (T, bool) {
+	checkBitLen(key, n)
+	node := t.trie.get(key, n)
+	if node == nil {
+		var zero T
+		return zero, false
+	}
+	return node.value, true
+}
+
+// Put inserts the value into the tree at the key formed by the first n bits of
+// key, replacing any existing value. It returns true if it adds a new value,
+// false if it replaces an existing value.
+func (t *BitTree[T]) Put(key []byte, n int, value T) bool {
+	checkBitLen(key, n)
+	return t.trie.put(key, n, value)
+}
+
+// Delete removes the value associated with the key formed by the first n bits
+// of key. Returns true if there was a value stored for the key.
+func (t *BitTree[T]) Delete(key []byte, n int) bool {
+	checkBitLen(key, n)
+	return t.trie.delete(key, n)
+}
+
+// Iter visits all values in the tree, yielding the key and value of each.
+//
+// The tree is traversed in order of key bits, with shorter keys before longer
+// keys that they prefix, making the output deterministic.
+func (t *BitTree[T]) Iter() iter.Seq2[BitKey, T] {
+	return func(yield func(BitKey, T) bool) {
+		t.trie.root.walk(bitYield(yield))
+	}
+}
+
+// IterAt visits all values whose keys match or are prefixed by the first n
+// bits of key, yielding the key and value of each.
+//
+// The tree is traversed in order of key bits, making the output deterministic.
+func (t *BitTree[T]) IterAt(key []byte, n int) iter.Seq2[BitKey, T] {
+	checkBitLen(key, n)
+	return func(yield func(BitKey, T) bool) {
+		t.trie.prefixed(key, n).walk(bitYield(yield))
+	}
+}
+
+// IterPath visits each value along the path from the root to the key formed
+// by the first n bits of key, yielding the key and value of each. These are
+// the values whose keys are a prefix of, or equal to, the given key.
+//
+// The values are visited from shortest to longest key.
+func (t *BitTree[T]) IterPath(key []byte, n int) iter.Seq2[BitKey, T] {
+	checkBitLen(key, n)
+	return func(yield func(BitKey, T) bool) {
+		t.trie.path(key, n, bitYield(yield))
+	}
+}
+
+func bitYield[T any](yield func(BitKey, T) bool) func(*bitNode[T]) bool {
+	return func(node *bitNode[T]) bool {
+		return yield(BitKey{key: node.key, n: node.n}, node.value)
+	}
+}
+
+// checkBitLen panics if n is not a valid number of bits for key.
+func checkBitLen(key []byte, n int) {
+	if n < 0 || n > len(key)*8 {
+		panic("radixtree: bit length out of range for key")
+	}
+}
@@ -0,0 +1,106 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitTree(t *testing.T) {
+	bt := NewBitTree[string]()
+	keys := []struct {
+		b []byte
+		n int
+		s string
+	}{
+		{nil, 0, ""},
+		{[]byte{0b10100000}, 3, "101"},
+		{[]byte{0b10111111}, 4, "1011"},
+		{[]byte{0b10110100, 0b01000000}, 10, "1011010001"},
+		{[]byte{0b10110100, 0b01111111}, 11, "10110100011"},
+		{[]byte{0b01000000}, 2, "01"},
+		{[]byte{0xff, 0xff}, 16, "1111111111111111"},
+	}
+	for _, k := range keys {
+		if !bt.Put(k.b, k.n, k.s) {
+			t.Fatalf("expected new key %s", k.s)
+		}
+	}
+	if bt.Len() != len(keys) {
+		t.Fatalf("expected %d keys, got %d", len(keys), bt.Len())
+	}
+	if bt.Put([]byte{0b10101111}, 3, "101") {
+		t.Fatal("expected existing key to be replaced")
+	}
+
+	for _, k := range keys {
+		val, ok := bt.Get(k.b, k.n)
+		if !ok || val != k.s {
+			t.Errorf("expected %s at key, got %s", k.s, val)
+		}
+	}
+	if _, ok := bt.Get([]byte{0b10110000}, 5); ok {
+		t.Fatal("expected no value for key 10110")
+	}
+	if _, ok := bt.Get([]byte{0b10000000}, 1); ok {
+		t.Fatal("expected no value for key 1")
+	}
+
+	var found []string
+	for k, v := range bt.Iter() {
+		if k.String() != v {
+			t.Fatalf("wrong value %s for key %s", v, k)
+		}
+		found = append(found, v)
+	}
+	expect := []string{"", "01", "101", "1011", "1011010001", "10110100011", "1111111111111111"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected %v, got %v", expect, found)
+	}
+
+	found = found[:0]
+	for k := range bt.IterAt([]byte{0b10110000}, 5) {
+		found = append(found, k.String())
+	}
+	expect = []string{"1011010001", "10110100011"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected %v, got %v", expect, found)
+	}
+
+	found = found[:0]
+	for k := range bt.IterPath([]byte{0b10110100, 0b01100000}, 12) {
+		found = append(found, k.String())
+	}
+	expect = []string{"", "101", "1011", "1011010001", "10110100011"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected %v, got %v", expect, found)
+	}
+
+	if !bt.Delete([]byte{0b10110000}, 4) {
+		t.Fatal("expected key 1011 to be deleted")
+	}
+	if bt.Delete([]byte{0b10110000}, 4) {
+		t.Fatal("key 1011 should already be deleted")
+	}
+	found = found[:0]
+	for k := range bt.IterAt([]byte{0b10000000}, 1) {
+		found = append(found, k.String())
+	}
+	expect = []string{"101", "1011010001", "10110100011", "1111111111111111"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected %v, got %v", expect, found)
+	}
+
+	k := BitKey{}
+	for k = range bt.IterAt([]byte{0xff, 0xff}, 16) {
+	}
+	if k.Len() != 16 || k.Bit(15) != 1 || !slices.Equal(k.Bytes(), []byte{0xff, 0xff}) {
+		t.Fatal("wrong key", k)
+	}
+
+	if !panics(func() { bt.Put([]byte{0}, 9, "") }) {
+		t.Fatal("expected panic for bit length longer than key")
+	}
+	if !panics(func() { k.Bit(16) }) {
+		t.Fatal("expected panic for bit index out of range")
+	}
+}
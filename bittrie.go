@@ -0,0 +1,231 @@
+package radixtree
+
+import (
+	"math/bits"
+)
+
+// bitTrie is a path-compressed binary trie, whose keys are sequences of bits
+// that need not end on a byte boundary. Each node stores its whole key, and
+// branches on the bit that follows it. A node exists only if it holds a value
+// or has two children, so the trie is compressed in the same way as the
+// prefix of a radixNode.
+type bitTrie[T any] struct {
+	root *bitNode[T]
+	size int
+}
+
+type bitNode[T any] struct {
+	// key holds the n bits of the node's key, in the most significant bits
+	// first, with unused bits of the last byte set to zero.
+	key      string
+	n        int
+	child    [2]*bitNode[T]
+	hasValue bool
+	value    T
+}
+
+// bitAt returns bit i of key.
+func bitAt[K ~string | ~[]byte](key K, i int) int {
+	return int(key[i>>3]>>(7-i&7)) & 1
+}
+
+// commonBits returns the number of leading bits in common between the first
+// an bits of a and the first bn bits of b.
+func commonBits[A, B ~string | ~[]byte](a A, an int, b B, bn int) int {
+	n := min(an, bn)
+	for i := 0; i < n; i += 8 {
+		if x := a[i>>3] ^ b[i>>3]; x != 0 {
+			return min(i+bits.LeadingZeros8(x), n)
+		}
+	}
+	return n
+}
+
+// bitKey returns the first n bits of key as a string, with unused bits of the
+// last byte set to zero.
+func bitKey(key []byte, n int) string {
+	b := make([]byte, (n+7)>>3)
+	copy(b, key)
+	if r := n & 7; r != 0 {
+		b[len(b)-1] &= 0xff << (8 - r)
+	}
+	return string(b)
+}
+
+// put stores the value at the first n bits of key. Returns true if a new value
+// was added, false if an existing value was replaced.
+func (t *bitTrie[T]) put(key []byte, n int, value T) bool {
+	np := &t.root
+	for {
+		node := *np
+		if node == nil {
+			*np = &bitNode[T]{
+				key:      bitKey(key, n),
+				n:        n,
+				hasValue: true,
+				value:    value,
+			}
+			t.size++
+			return true
+		}
+		c := commonBits(node.key, node.n, key, n)
+		if c == node.n {
+			if c == n {
+				isNew := !node.hasValue
+				if isNew {
+					t.size++
+				}
+				node.hasValue = true
+				node.value = value
+				return isNew
+			}
+			np = &node.child[bitAt(key, c)]
+			continue
+		}
+		// Key diverges from, or ends within, the node's key.
+		leaf := &bitNode[T]{
+			key:      bitKey(key, n),
+			n:        n,
+			hasValue: true,
+			value:    value,
+		}
+		if c == n {
+			leaf.child[bitAt(node.key, c)] = node
+			*np = leaf
+		} else {
+			branch := &bitNode[T]{
+				key: bitKey(key, c),
+				n:   c,
+			}
+			branch.child[bitAt(node.key, c)] = node
+			branch.child[bitAt(key, c)] = leaf
+			*np = branch
+		}
+		t.size++
+		return true
+	}
+}
+
+// get returns the node whose key is exactly the first n bits of key, or nil.
+func (t *bitTrie[T]) get(key []byte, n int) *bitNode[T] {
+	node := t.root
+	for node != nil && node.n <= n && commonBits(node.key, node.n, key, n) == node.n {
+		if node.n == n {
+			if node.hasValue {
+				return node
+			}
+			return nil
+		}
+		node = node.child[bitAt(key, node.n)]
+	}
+	return nil
+}
+
+// delete removes the value at the first n bits of key. Returns true if there
+// was a value stored.
+func (t *bitTrie[T]) delete(key []byte, n int) bool {
+	var parent **bitNode[T]
+	np := &t.root
+	for {
+		node := *np
+		if node == nil || node.n > n || commonBits(node.key, node.n, key, n) != node.n {
+			return false
+		}
+		if node.n < n {
+			parent = np
+			np = &node.child[bitAt(key, node.n)]
+			continue
+		}
+		if !node.hasValue {
+			return false
+		}
+		var zero T
+		node.hasValue = false
+		node.value = zero
+		t.size--
+
+		// Remove the node if it no longer branches, and its parent if that
+		// no longer branches as a result.
+		switch {
+		case node.child[0] != nil && node.child[1] != nil:
+		case node.child[0] != nil:
+			*np = node.child[0]
+		case node.child[1] != nil:
+			*np = node.child[1]
+		default:
+			*np = nil
+			if parent != nil && !(*parent).hasValue {
+				p := *parent
+				if p.child[0] != nil {
+					*parent = p.child[0]
+				} else {
+					*parent = p.child[1]
+				}
+			}
+		}
+		return true
+	}
+}
+
+// path visits each node with a value whose key is a prefix of, or equal to,
+// the first n bits of key, from shortest to longest. Returns false if
+// iteration was stopped.
+func (t *bitTrie[T]) path(key []byte, n int, yield func(*bitNode[T]) bool) bool {
+	node := t.root
+	for node != nil && node.n <= n && commonBits(node.key, node.n, key, n) == node.n {
+		if node.hasValue && !yield(node) {
+			return false
+		}
+		if node.n == n {
+			break
+		}
+		node = node.child[bitAt(key, node.n)]
+	}
+	return true
+}
+
+// longest returns the node with a value whose key is the longest prefix of the
+// first n bits of key, or nil if there is none.
+func (t *bitTrie[T]) longest(key []byte, n int) *bitNode[T] {
+	var found *bitNode[T]
+	node := t.root
+	for node != nil && node.n <= n && commonBits(node.key, node.n, key, n) == node.n {
+		if node.hasValue {
+			found = node
+		}
+		if node.n == n {
+			break
+		}
+		node = node.child[bitAt(key, node.n)]
+	}
+	return found
+}
+
+// prefixed returns the highest node whose key is prefixed by the first n bits
+// of key. All keys prefixed by these bits are in the node's subtree.
+func (t *bitTrie[T]) prefixed(key []byte, n int) *bitNode[T] {
+	node := t.root
+	for node != nil {
+		c := commonBits(node.key, node.n, key, n)
+		if c == n {
+			return node
+		}
+		if c < node.n {
+			return nil
+		}
+		node = node.child[bitAt(key, node.n)]
+	}
+	return nil
+}
+
+// walk visits each node with a value in the subtree, in order of key bits.
+// Returns false if iteration was stopped.
+func (node *bitNode[T]) walk(yield func(*bitNode[T]) bool) bool {
+	if node == nil {
+		return true
+	}
+	if node.hasValue && !yield(node) {
+		return false
+	}
+	return node.child[0].walk(yield) && node.child[1].walk(yield)
+}
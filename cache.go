@@ -0,0 +1,169 @@
+package radixtree
+
+import "iter"
+
+// Cache is a size-bounded cache of values by key, which evicts the least
+// recently used value when it is full. Values are stored in a Tree, so all
+// values whose keys have a prefix can be invalidated at once by DeletePrefix.
+//
+// Recency is tracked by a list threaded through the cache entries, so no
+// memory is allocated to update it. Because Get updates the list, a Cache is
+// not safe for concurrent use, even by readers, without synchronization.
+type Cache[T any] struct {
+	tree     Tree[*cacheEntry[T]]
+	capacity int
+	// lru is the sentinel of the circular recency list. lru.next is the most
+	// recently used entry and lru.prev is the least recently used.
+	lru cacheEntry[T]
+}
+
+type cacheEntry[T any] struct {
+	key        string
+	value      T
+	prev, next *cacheEntry[T]
+}
+
+// NewCache creates a new cache that holds up to capacity values. Panics if
+// capacity is less than 1.
+func NewCache[T any](capacity int) *Cache[T] {
+	if capacity < 1 {
+		panic("radixtree: cache capacity must be positive")
+	}
+	c := &Cache[T]{capacity: capacity}
+	c.lru.prev = &c.lru
+	c.lru.next = &c.lru
+	return c
+}
+
+// Len returns the number of values in the cache.
+func (c *Cache[T]) Len() int {
+	return c.tree.Len()
+}
+
+// Cap returns the maximum number of values that the cache holds.
+func (c *Cache[T]) Cap() int {
+	return c.capacity
+}
+
+// Get returns the value stored at the given key, and marks it as the most
+// recently used. Returns false if there is no value present for the key.
+func (c *Cache[T]) Get(key string) (T, bool) {
+	e, ok := c.tree.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	c.unlink(e)
+	c.pushFront(e)
+	return e.value, true
+}
+
+// Peek returns the value stored at the given key, without changing how
+// recently it was used. Returns false if there is no value present for the
+// key.
+func (c *Cache[T]) Peek(key string) (T, bool) {
+	e, ok := c.tree.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Put stores the value at the given key, replacing any existing value, and
+// marks it as the most recently used. If this adds a value to a full cache,
+// then the least recently used value is evicted. It returns true if it adds a
+// new value, false if it replaces an existing value.
+func (c *Cache[T]) Put(key string, value T) bool {
+	if e, ok := c.tree.Get(key); ok {
+		e.value = value
+		c.unlink(e)
+		c.pushFront(e)
+		return false
+	}
+	if c.tree.Len() == c.capacity {
+		oldest := c.lru.prev
+		c.unlink(oldest)
+		c.tree.Delete(oldest.key)
+	}
+	e := &cacheEntry[T]{
+		key:   key,
+		value: value,
+	}
+	c.pushFront(e)
+	c.tree.Put(key, e)
+	return true
+}
+
+// Delete removes the value stored at the given key. Returns true if there was
+// a value stored for the key.
+func (c *Cache[T]) Delete(key string) bool {
+	e, ok := c.tree.Get(key)
+	if !ok {
+		return false
+	}
+	c.unlink(e)
+	return c.tree.Delete(key)
+}
+
+// DeletePrefix removes all values whose key is prefixed by the given prefix.
+// Returns the number of values removed.
+func (c *Cache[T]) DeletePrefix(prefix string) int {
+	var n int
+	for _, e := range c.tree.IterAt(prefix) {
+		c.unlink(e)
+		n++
+	}
+	if n != 0 {
+		c.tree.DeletePrefix(prefix)
+	}
+	return n
+}
+
+// IterAt visits all values whose keys match or are prefixed by the specified
+// key, yielding the key and value of each, without changing how recently they
+// were used.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (c *Cache[T]) IterAt(key string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for key, e := range c.tree.IterAt(key) {
+			if !yield(key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Recent visits all values from the most to the least recently used, yielding
+// the key and value of each, without changing how recently they were used.
+//
+// The body of a loop over Recent may delete the key being visited. Calling Get
+// or Put, or deleting any other key, reorders or unlinks the entries being
+// walked, and is not supported.
+func (c *Cache[T]) Recent() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for e := c.lru.next; e != &c.lru; {
+			// Save the next entry, since deleting e unlinks it.
+			next := e.next
+			if !yield(e.key, e.value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+func (c *Cache[T]) pushFront(e *cacheEntry[T]) {
+	e.prev = &c.lru
+	e.next = c.lru.next
+	e.next.prev = e
+	c.lru.next = e
+}
+
+func (c *Cache[T]) unlink(e *cacheEntry[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+}
@@ -0,0 +1,127 @@
+package radixtree
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func recentKeys[T any](c *Cache[T]) []string {
+	var keys []string
+	for key := range c.Recent() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache[int](3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	if got, want := recentKeys(c), []string{"c", "b", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Get promotes, so b is evicted instead of a.
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatal("expected a in cache")
+	}
+	if !c.Put("d", 4) {
+		t.Fatal("expected d to be added")
+	}
+	if _, ok := c.Peek("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if c.Len() != 3 {
+		t.Fatalf("expected 3 values, got %d", c.Len())
+	}
+
+	// Peek does not promote, and replacing a value promotes it.
+	c.Peek("c")
+	if c.Put("a", 11) {
+		t.Fatal("expected a to be replaced")
+	}
+	if got, want := recentKeys(c), []string{"a", "d", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	c.Put("e", 5)
+	if _, ok := c.Peek("c"); ok {
+		t.Fatal("expected c to be evicted")
+	}
+
+	if !c.Delete("d") || c.Delete("d") {
+		t.Fatal("wrong result from Delete")
+	}
+	if got, want := recentKeys(c), []string{"e", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCacheDeletePrefix(t *testing.T) {
+	c := NewCache[int](5)
+	for i := range 5 {
+		c.Put("user/"+strconv.Itoa(i), i)
+	}
+	c.Get("user/1")
+	if n := c.DeletePrefix("user/0"); n != 1 {
+		t.Fatalf("expected 1 value removed, got %d", n)
+	}
+	c.Put("group/a", 10)
+	c.Put("group/b", 11)
+	if got, want := recentKeys(c), []string{"group/b", "group/a", "user/1", "user/4", "user/3"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if n := c.DeletePrefix("user/"); n != 3 {
+		t.Fatalf("expected 3 values removed, got %d", n)
+	}
+	if n := c.DeletePrefix("user/"); n != 0 {
+		t.Fatalf("expected nothing removed, got %d", n)
+	}
+	if got, want := recentKeys(c), []string{"group/b", "group/a"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Removed entries must not be evicted later.
+	for i := range 3 {
+		c.Put("x"+strconv.Itoa(i), i)
+	}
+	c.Put("y", 0)
+	if c.Len() != 5 {
+		t.Fatalf("expected 5 values, got %d", c.Len())
+	}
+	if _, ok := c.Peek("group/b"); !ok {
+		t.Fatal("expected group/b in cache")
+	}
+	if _, ok := c.Peek("group/a"); ok {
+		t.Fatal("expected group/a to be evicted")
+	}
+	var n int
+	for range c.IterAt("x") {
+		n++
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 values with prefix x, got %d", n)
+	}
+}
+
+func TestCacheRecentDelete(t *testing.T) {
+	c := NewCache[int](4)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		c.Put(key, i)
+	}
+	var visited []string
+	for key := range c.Recent() {
+		visited = append(visited, key)
+		if key != "b" {
+			c.Delete(key)
+		}
+	}
+	if want := []string{"d", "c", "b", "a"}; !slices.Equal(visited, want) {
+		t.Fatalf("expected %q visited, got %q", want, visited)
+	}
+	if got := recentKeys(c); !slices.Equal(got, []string{"b"}) || c.Len() != 1 {
+		t.Fatalf("expected only b remaining, got %q", got)
+	}
+}
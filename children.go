@@ -0,0 +1,97 @@
+package radixtree
+
+import (
+	"iter"
+	"strings"
+)
+
+// IterChildren visits the immediate children of the prefix, treating keys as
+// paths separated by sep, in the same way as listing a directory. For each key
+// that has no sep after the prefix, the key and its Item are yielded. Keys that
+// have a sep after the prefix are collapsed into a common prefix, which ends at
+// that first sep and is yielded once with a nil Item. The subtree below a
+// common prefix is skipped without being visited.
+//
+// Only keys and common prefixes that are lexically greater than startAfter are
+// yielded. Pass the last string yielded as startAfter to resume a listing, or
+// an empty string to start from the beginning. Subtrees that lie entirely
+// before startAfter are skipped.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *Tree[T]) IterChildren(prefix string, sep byte, startAfter string) iter.Seq2[string, *Item[T]] {
+	return func(yield func(string, *Item[T]) bool) {
+		node, rem := t.locate(prefix)
+		if node == nil || (node.leaf == nil && len(node.nodes) == 0) {
+			return
+		}
+		c := childWalk[T]{
+			sep:        sep,
+			startAfter: startAfter,
+			yield:      checkedYield(t, yield),
+		}
+		if i := strings.IndexByte(rem, sep); i != -1 {
+			c.common(node, len(prefix)+i+1)
+			return
+		}
+		c.walk(node, len(prefix)+len(rem))
+	}
+}
+
+type childWalk[T any] struct {
+	sep        byte
+	startAfter string
+	yield      func(string, *Item[T]) bool
+}
+
+// walk visits the node, whose key is depth bytes long, and its children.
+// Returns false if iteration was stopped.
+func (c *childWalk[T]) walk(node *radixNode[T], depth int) bool {
+	if c.startAfter != "" {
+		// Skip the subtree if all of its keys are before startAfter.
+		key := node.firstItem().key[:depth]
+		if key < c.startAfter && !strings.HasPrefix(c.startAfter, key) {
+			return true
+		}
+	}
+	if node.leaf != nil && node.leaf.key > c.startAfter && !c.yield(node.leaf.key, node.leaf) {
+		return false
+	}
+	for i, child := range node.nodes {
+		if node.radices[i] == c.sep {
+			if !c.common(child, depth+1) {
+				return false
+			}
+			continue
+		}
+		if j := strings.IndexByte(child.prefix, c.sep); j != -1 {
+			if !c.common(child, depth+1+j+1) {
+				return false
+			}
+			continue
+		}
+		if !c.walk(child, depth+1+len(child.prefix)) {
+			return false
+		}
+	}
+	return true
+}
+
+// common yields the first n bytes of the keys in the node's subtree as a common
+// prefix. Returns false if iteration was stopped.
+func (c *childWalk[T]) common(node *radixNode[T], n int) bool {
+	key := node.firstItem().key[:n]
+	if key <= c.startAfter {
+		return true
+	}
+	return c.yield(key, nil)
+}
+
+// firstItem returns the first item, in lexical order, in the node's subtree.
+// Every node other than an empty root has an item in its subtree, so this must
+// not be called for an empty root.
+func (node *radixNode[T]) firstItem() *Item[T] {
+	for node.leaf == nil {
+		node = node.nodes[0]
+	}
+	return node.leaf
+}
@@ -0,0 +1,116 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIterChildren(t *testing.T) {
+	rt := New[int]()
+	for i, key := range []string{
+		"docs/",
+		"docs/a.txt",
+		"docs/b.txt",
+		"docs/img/logo.png",
+		"docs/img/icon.png",
+		"docs/imgs",
+		"docs/old/x/y.txt",
+		"docs/z",
+		"src/main.go",
+		"readme",
+	} {
+		rt.Put(key, i)
+	}
+
+	list := func(prefix, startAfter string) []string {
+		var out []string
+		for key, item := range rt.IterChildren(prefix, '/', startAfter) {
+			if item == nil {
+				key += " (dir)"
+			} else if item.Key() != key {
+				t.Fatalf("yielded key %q for item %q", key, item.Key())
+			}
+			out = append(out, key)
+		}
+		return out
+	}
+
+	got := list("", "")
+	want := []string{"docs/ (dir)", "readme", "src/ (dir)"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	got = list("docs/", "")
+	want = []string{"docs/", "docs/a.txt", "docs/b.txt", "docs/img/ (dir)", "docs/imgs", "docs/old/ (dir)", "docs/z"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	got = list("docs/i", "")
+	want = []string{"docs/img/ (dir)", "docs/imgs"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Prefix ending inside a node prefix that contains the separator.
+	got = list("docs/o", "")
+	want = []string{"docs/old/ (dir)"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if got = list("nothing", ""); len(got) != 0 {
+		t.Fatalf("expected nothing, got %q", got)
+	}
+
+	// Paginate two at a time, resuming after the last key yielded.
+	var pages []string
+	after := ""
+	for {
+		var n int
+		for key := range rt.IterChildren("docs/", '/', after) {
+			pages = append(pages, key)
+			after = key
+			if n++; n == 2 {
+				break
+			}
+		}
+		if n == 0 {
+			break
+		}
+	}
+	want = []string{"docs/", "docs/a.txt", "docs/b.txt", "docs/img/", "docs/imgs", "docs/old/", "docs/z"}
+	if !slices.Equal(pages, want) {
+		t.Fatalf("expected pages %q, got %q", want, pages)
+	}
+
+	got = list("docs/", "docs/img/logo")
+	want = []string{"docs/imgs", "docs/old/ (dir)", "docs/z"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if got = list("docs/", "docs/zz"); len(got) != 0 {
+		t.Fatalf("expected nothing after last key, got %q", got)
+	}
+}
+
+func TestIterChildrenEmpty(t *testing.T) {
+	rt := New[int]()
+	for _, after := range []string{"", "x"} {
+		for range rt.IterChildren("", '/', after) {
+			t.Fatal("expected nothing from empty tree")
+		}
+	}
+
+	// Tree that becomes empty after the last key is deleted.
+	rt.Put("a/b", 1)
+	rt.Delete("a/b")
+	for range rt.IterChildren("", '/', "x") {
+		t.Fatal("expected nothing from emptied tree")
+	}
+	for range rt.IterChildren("a/", '/', "x") {
+		t.Fatal("expected nothing from emptied tree")
+	}
+}
@@ -10,6 +10,8 @@
 // Read operations (Get, Iter, IterAt, IterPath) allocate no heap memory
 // and are safe to call concurrently. Write operations are not synchronized;
 // callers that mix reads and writes must coordinate access themselves.
+// Modifying the tree invalidates any Stepper or running iterator. Enable
+// checked mode with SetChecked to panic when an invalidated one is used.
 //
 // The API accepts string keys. Because strings are immutable, the tree
 // stores them directly without copying.
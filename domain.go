@@ -0,0 +1,185 @@
+package radixtree
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// DomainTree stores rules for DNS domain names, such as blocklist entries, and
+// finds the most specific rule that matches a name. Rules are stored with
+// their labels reversed, so that "www.example.com" is stored as
+// "com.example.www" and names under the same domain share storage.
+//
+// A rule matches names as follows:
+//
+//	example.com     matches example.com and every name below it
+//	*.example.com   matches every name below example.com, but not example.com
+//
+// Rules and names are normalized by folding ASCII letters to lower case and
+// removing a single trailing dot. Internationalized names are not converted,
+// and should be given in their ASCII punycode form ("xn--...").
+type DomainTree[T any] struct {
+	tree Tree[domainRule[T]]
+}
+
+type domainRule[T any] struct {
+	rule  string
+	value T
+}
+
+// NewDomainTree creates a new domain name tree.
+func NewDomainTree[T any]() *DomainTree[T] {
+	return new(DomainTree[T])
+}
+
+// Len returns the number of rules stored in the tree.
+func (d *DomainTree[T]) Len() int {
+	return d.tree.Len()
+}
+
+// Add stores the value for the rule, replacing any existing value for the
+// rule. Returns an error if the rule has an empty label, or a '*' label other
+// than as the first label.
+func (d *DomainTree[T]) Add(rule string, value T) error {
+	rule, key, err := domainKey(rule)
+	if err != nil {
+		return err
+	}
+	d.tree.Put(key, domainRule[T]{
+		rule:  rule,
+		value: value,
+	})
+	return nil
+}
+
+// Get returns the value stored for exactly the given rule. Returns false if
+// there is no value present for the rule.
+func (d *DomainTree[T]) Get(rule string) (T, bool) {
+	_, key, err := domainKey(rule)
+	if err != nil {
+		var zero T
+		return zero, false
+	}
+	dr, ok := d.tree.Get(key)
+	return dr.value, ok
+}
+
+// Remove removes the rule. Returns true if the rule was stored.
+func (d *DomainTree[T]) Remove(rule string) bool {
+	_, key, err := domainKey(rule)
+	if err != nil {
+		return false
+	}
+	return d.tree.Delete(key)
+}
+
+// Iter visits all rules in the tree, yielding the normalized rule and value of
+// each.
+//
+// Rules are visited in lexical order of their reversed labels, so that rules
+// for the same domain are visited together.
+func (d *DomainTree[T]) Iter() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for _, dr := range d.tree.Iter() {
+			if !yield(dr.rule, dr.value) {
+				return
+			}
+		}
+	}
+}
+
+// MatchSuffix finds the most specific rule that matches the name, and returns
+// the normalized rule and its value. A rule for more labels is more specific
+// than one for fewer labels, and a rule naming a label is more specific than a
+// wildcard in its place. Returns false if no rule matches.
+//
+// The name is matched from its last label to its first without being copied,
+// so no memory is allocated.
+func (d *DomainTree[T]) MatchSuffix(name string) (string, T, bool) {
+	var (
+		best *Item[domainRule[T]]
+		zero T
+	)
+	name = strings.TrimSuffix(name, ".")
+	pos := nodePos[domainRule[T]]{node: &d.tree.root}
+	for end, level := len(name), 0; ; level++ {
+		start := strings.LastIndexByte(name[:end], '.') + 1
+		if start == end {
+			// Empty label.
+			return "", zero, false
+		}
+
+		// A wildcard at this level matches the label.
+		wild, ok := pos, true
+		if level != 0 {
+			wild, ok = wild.next('.')
+		}
+		if ok {
+			if wild, ok = wild.next('*'); ok {
+				if item := wild.item(); item != nil {
+					best = item
+				}
+			}
+		}
+
+		// Consume the label, to find a rule that names it.
+		ok = true
+		if level != 0 {
+			if pos, ok = pos.next('.'); !ok {
+				break
+			}
+		}
+		for i := start; i < end && ok; i++ {
+			pos, ok = pos.next(lowerASCII(name[i]))
+		}
+		if !ok {
+			break
+		}
+		if item := pos.item(); item != nil {
+			best = item
+		}
+		if start == 0 {
+			break
+		}
+		end = start - 1
+	}
+	if best == nil {
+		return "", zero, false
+	}
+	return best.value.rule, best.value.value, true
+}
+
+// domainKey normalizes the rule, and returns it and its tree key with labels
+// reversed.
+func domainKey(rule string) (string, string, error) {
+	rule = strings.Map(func(r rune) rune {
+		if r < 0x80 {
+			return rune(lowerASCII(byte(r)))
+		}
+		return r
+	}, strings.TrimSuffix(rule, "."))
+	if rule == "" {
+		return "", "", fmt.Errorf("radixtree: empty domain rule")
+	}
+	labels := strings.Split(rule, ".")
+	for i, label := range labels {
+		if label == "" {
+			return "", "", fmt.Errorf("radixtree: empty label in domain rule %q", rule)
+		}
+		if strings.Contains(label, "*") && (label != "*" || i != 0) {
+			return "", "", fmt.Errorf("radixtree: wildcard must be first label in domain rule %q", rule)
+		}
+	}
+	slices.Reverse(labels)
+	return rule, strings.Join(labels, "."), nil
+}
+
+// lowerASCII returns the lower case of an ASCII letter, or c unchanged.
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,108 @@
+package radixtree
+
+import (
+	"testing"
+)
+
+func TestDomainTree(t *testing.T) {
+	d := NewDomainTree[int]()
+	rules := []string{
+		"example.com",
+		"*.example.com",
+		"www.example.com",
+		"ads.example.com.",
+		"Tracker.NET",
+		"*.cdn.tracker.net",
+		"xn--bcher-kva.example",
+		"*",
+	}
+	for i, r := range rules {
+		if err := d.Add(r, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d.Len() != len(rules) {
+		t.Fatalf("expected %d rules, got %d", len(rules), d.Len())
+	}
+
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"example.com", "example.com"},
+		{"EXAMPLE.com.", "example.com"},
+		{"mail.example.com", "*.example.com"},
+		{"a.mail.example.com", "*.example.com"},
+		{"www.example.com", "www.example.com"},
+		{"x.www.example.com", "www.example.com"},
+		{"ads.example.com", "ads.example.com"},
+		{"tracker.net", "tracker.net"},
+		{"cdn.tracker.net", "tracker.net"},
+		{"a.cdn.tracker.net", "*.cdn.tracker.net"},
+		{"shop.xn--bcher-kva.example", "xn--bcher-kva.example"},
+		{"other.org", "*"},
+		{"org", "*"},
+		{"examplexcom", "*"},
+	}
+	for _, tc := range tests {
+		rule, val, ok := d.MatchSuffix(tc.name)
+		if !ok || rule != tc.rule {
+			t.Errorf("name %q: expected rule %q, got %q", tc.name, tc.rule, rule)
+			continue
+		}
+		if val != d.mustGet(t, rule) {
+			t.Errorf("name %q: wrong value %d", tc.name, val)
+		}
+	}
+
+	for _, bad := range []string{"", ".", "a..example.com", ".example.com"} {
+		if _, _, ok := d.MatchSuffix(bad); ok {
+			t.Errorf("expected no match for invalid name %q", bad)
+		}
+	}
+
+	if !d.Remove("*") || d.Remove("*") {
+		t.Fatal("expected rule \"*\" to be removed once")
+	}
+	if _, _, ok := d.MatchSuffix("other.org"); ok {
+		t.Fatal("expected no match after removing \"*\"")
+	}
+	if !d.Remove("*.example.com") {
+		t.Fatal("expected rule to be removed")
+	}
+	if rule, _, _ := d.MatchSuffix("mail.example.com"); rule != "example.com" {
+		t.Fatal("expected match for example.com, got", rule)
+	}
+
+	var rules2 []string
+	for r := range d.Iter() {
+		rules2 = append(rules2, r)
+	}
+	if len(rules2) != d.Len() || rules2[0] != "example.com" {
+		t.Fatal("wrong rules from Iter:", rules2)
+	}
+
+	for _, bad := range []string{"", "a..b", "a.*.com", "w*.example.com", "."} {
+		if err := d.Add(bad, 0); err == nil {
+			t.Errorf("expected error adding rule %q", bad)
+		}
+	}
+	if _, ok := d.Get("a..b"); ok {
+		t.Fatal("should not get invalid rule")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		d.MatchSuffix("x.WWW.example.com.")
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func (d *DomainTree[T]) mustGet(t *testing.T, rule string) T {
+	val, ok := d.Get(rule)
+	if !ok {
+		t.Fatalf("expected value for rule %q", rule)
+	}
+	return val
+}
@@ -0,0 +1,419 @@
+package radixtree
+
+import (
+	"container/heap"
+	"iter"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExpiringTree is a radix tree in which values may be given a time to live,
+// after which they expire. Expired values are hidden from lookups and
+// iteration immediately. An expired value that is accessed by Get or an
+// iterator is removed from the tree. Sweep removes all expired values, which
+// it finds using an index ordered by expiry time. Call Sweep periodically, or
+// start a background goroutine to do so with StartSweeper, to remove expired
+// values that are not accessed.
+//
+// An ExpiringTree is safe for concurrent use. Iterators collect values in
+// batches of up to 64 while holding a read lock, and yield each batch after
+// releasing it, so the body of a loop over an iterator may call any method of
+// the tree. Values that are put or deleted during iteration may or may not be
+// visited.
+type ExpiringTree[T any] struct {
+	mu      sync.RWMutex
+	tree    Tree[*expiringEntry[T]]
+	expiry  expiryHeap[T]
+	now     func() time.Time
+	onEvict func(key string, value T)
+}
+
+type expiringEntry[T any] struct {
+	key     string
+	value   T
+	expires time.Time
+	// index is the position of the entry in the expiry heap, or -1 if the
+	// entry does not expire.
+	index int
+}
+
+// NewExpiringTree creates a new radix tree with expiring values.
+func NewExpiringTree[T any]() *ExpiringTree[T] {
+	return new(ExpiringTree[T])
+}
+
+// SetClock sets the function used to get the current time, which is time.Now
+// by default. This allows expiry to be controlled in tests.
+func (t *ExpiringTree[T]) SetClock(now func() time.Time) {
+	t.mu.Lock()
+	t.now = now
+	t.mu.Unlock()
+}
+
+// SetEvictFunc sets a function that is called with the key and value of each
+// expired value that is removed from the tree, whether by Sweep or when the
+// value is accessed. The function is called after the values are removed,
+// without the tree locked.
+func (t *ExpiringTree[T]) SetEvictFunc(onEvict func(key string, value T)) {
+	t.mu.Lock()
+	t.onEvict = onEvict
+	t.mu.Unlock()
+}
+
+// Len returns the number of unexpired values stored in the tree. Expired
+// values that have not been removed are counted using the expiry index, in
+// time proportional to their number, and are not removed.
+func (t *ExpiringTree[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Len() - t.expiry.countExpired(0, t.clock())
+}
+
+// Get returns the value stored at the given key. Returns false if there is no
+// value present for the key, or the value has expired, in which case the
+// expired value is removed from the tree.
+func (t *ExpiringTree[T]) Get(key string) (T, bool) {
+	t.mu.RLock()
+	e, ok := t.tree.Get(key)
+	expired := ok && e.expired(t.clock())
+	t.mu.RUnlock()
+	if !ok || expired {
+		if expired {
+			t.evict([]*expiringEntry[T]{e})
+		}
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Put inserts the value into the tree at the given key, with no expiry,
+// replacing any existing value. It returns true if it adds a new value, false
+// if it replaces an existing unexpired value. An expired value that is
+// replaced is evicted.
+func (t *ExpiringTree[T]) Put(key string, value T) bool {
+	return t.put(key, value, 0, false)
+}
+
+// PutWithTTL inserts the value into the tree at the given key, replacing any
+// existing value. The value expires once the ttl has elapsed. It returns true
+// if it adds a new value, false if it replaces an existing unexpired value. An
+// expired value that is replaced is evicted.
+func (t *ExpiringTree[T]) PutWithTTL(key string, value T, ttl time.Duration) bool {
+	return t.put(key, value, ttl, true)
+}
+
+func (t *ExpiringTree[T]) put(key string, value T, ttl time.Duration, expire bool) bool {
+	t.mu.Lock()
+	now := t.clock()
+	var expires time.Time
+	if expire {
+		expires = now.Add(ttl)
+	}
+	// An expired value that has not been removed is evicted by the put, which
+	// then adds a new value.
+	old, ok := t.tree.Get(key)
+	if ok {
+		t.unindex(old)
+		if !old.expired(now) {
+			old = nil
+		}
+	}
+	e := &expiringEntry[T]{
+		key:     key,
+		value:   value,
+		expires: expires,
+		index:   -1,
+	}
+	if !expires.IsZero() {
+		heap.Push(&t.expiry, e)
+	}
+	added := t.tree.Put(key, e) || old != nil
+	onEvict := t.onEvict
+	t.mu.Unlock()
+
+	if old != nil && onEvict != nil {
+		onEvict(old.key, old.value)
+	}
+	return added
+}
+
+// Delete removes the value associated with the given key. Returns true if
+// there was a value stored for the key, even if it had expired.
+func (t *ExpiringTree[T]) Delete(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.tree.Get(key)
+	if !ok {
+		return false
+	}
+	t.unindex(e)
+	return t.tree.Delete(key)
+}
+
+// DeletePrefix removes all values whose key is prefixed by the given prefix.
+// Returns true if any values were removed.
+func (t *ExpiringTree[T]) DeletePrefix(prefix string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, e := range t.tree.IterAt(prefix) {
+		t.unindex(e)
+	}
+	return t.tree.DeletePrefix(prefix)
+}
+
+// Iter visits all unexpired values in the tree, yielding the key and value of
+// each. Expired values that are visited are removed from the tree.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *ExpiringTree[T]) Iter() iter.Seq2[string, T] {
+	return t.IterAt("")
+}
+
+// IterAt visits all unexpired values whose keys match or are prefixed by the
+// specified key, yielding the key and value of each. Expired values that are
+// visited are removed from the tree.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+// Each batch of values resumes the traversal after the last key visited, so
+// stopping early does not visit the remaining values.
+func (t *ExpiringTree[T]) IterAt(key string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		var (
+			b     expiringBatch[T]
+			after string
+		)
+		for first := true; ; first = false {
+			t.mu.RLock()
+			b.reset(t.clock())
+			collect := func(key string, e *expiringEntry[T]) bool {
+				after = key
+				return b.add(e)
+			}
+			done := true
+			if node, rem := t.tree.locate(key); node != nil && (node.leaf != nil || len(node.nodes) != 0) {
+				if first {
+					done = node.walk(collect)
+				} else {
+					done = node.walkAfter(len(key)+len(rem), after, collect)
+				}
+			}
+			t.mu.RUnlock()
+
+			if !b.yield(t, yield) || done {
+				return
+			}
+		}
+	}
+}
+
+// IterPath visits each unexpired value along the path from the root to the
+// given key, yielding the key and value of each. Expired values that are
+// visited are removed from the tree.
+func (t *ExpiringTree[T]) IterPath(key string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		var b expiringBatch[T]
+		t.mu.RLock()
+		b.reset(t.clock())
+		// The path is bounded by the key length, so is collected in one
+		// batch.
+		for _, e := range t.tree.IterPath(key) {
+			b.add(e)
+		}
+		t.mu.RUnlock()
+		b.yield(t, yield)
+	}
+}
+
+// expiringBatch holds the entries collected by an iterator while the tree is
+// read locked, separated into live and expired entries.
+type expiringBatch[T any] struct {
+	now     time.Time
+	live    []*expiringEntry[T]
+	expired []*expiringEntry[T]
+}
+
+// expiringBatchSize is the greatest number of entries collected by an
+// iterator while holding the read lock.
+const expiringBatchSize = 64
+
+func (b *expiringBatch[T]) reset(now time.Time) {
+	b.now = now
+	b.live = b.live[:0]
+	b.expired = b.expired[:0]
+}
+
+// add adds the entry to the batch. Returns false if the batch is full.
+func (b *expiringBatch[T]) add(e *expiringEntry[T]) bool {
+	if e.expired(b.now) {
+		b.expired = append(b.expired, e)
+	} else {
+		b.live = append(b.live, e)
+	}
+	return len(b.live)+len(b.expired) < expiringBatchSize
+}
+
+// yield removes the expired entries of the batch from the tree, and yields the
+// live entries. Returns false if iteration was stopped.
+func (b *expiringBatch[T]) yield(t *ExpiringTree[T], yield func(string, T) bool) bool {
+	t.evict(b.expired)
+	for _, e := range b.live {
+		if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkAfter visits the items in the node's subtree, whose key is depth bytes
+// long, that have keys greater than after. Subtrees that lie entirely before
+// after are skipped. Returns false if iteration was stopped.
+func (node *radixNode[T]) walkAfter(depth int, after string, yield func(string, T) bool) bool {
+	key := node.firstItem().key[:depth]
+	if key < after && !strings.HasPrefix(after, key) {
+		return true
+	}
+	if node.leaf != nil && node.leaf.key > after && !yield(node.leaf.key, node.leaf.value) {
+		return false
+	}
+	for _, child := range node.nodes {
+		if !child.walkAfter(depth+1+len(child.prefix), after, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// evict removes the given expired entries from the tree, if they are still
+// stored and expired, and calls the eviction function for each one removed.
+func (t *ExpiringTree[T]) evict(entries []*expiringEntry[T]) {
+	if len(entries) == 0 {
+		return
+	}
+	t.mu.Lock()
+	now := t.clock()
+	evicted := entries[:0]
+	for _, e := range entries {
+		if cur, ok := t.tree.Get(e.key); ok && cur == e && e.expired(now) {
+			t.unindex(e)
+			t.tree.Delete(e.key)
+			evicted = append(evicted, e)
+		}
+	}
+	onEvict := t.onEvict
+	t.mu.Unlock()
+
+	if onEvict != nil {
+		for _, e := range evicted {
+			onEvict(e.key, e.value)
+		}
+	}
+}
+
+// Sweep removes all expired values from the tree, calling the eviction
+// function, if set, for each. Returns the number of values removed.
+func (t *ExpiringTree[T]) Sweep() int {
+	t.mu.Lock()
+	now := t.clock()
+	var evicted []*expiringEntry[T]
+	for len(t.expiry) != 0 && t.expiry[0].expired(now) {
+		e := heap.Pop(&t.expiry).(*expiringEntry[T])
+		t.tree.Delete(e.key)
+		evicted = append(evicted, e)
+	}
+	onEvict := t.onEvict
+	t.mu.Unlock()
+
+	if onEvict != nil {
+		for _, e := range evicted {
+			onEvict(e.key, e.value)
+		}
+	}
+	return len(evicted)
+}
+
+// StartSweeper starts a goroutine that calls Sweep at the given interval.
+// Calling the returned function stops the goroutine, and waits for it to
+// exit.
+func (t *ExpiringTree[T]) StartSweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.Sweep()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
+func (t *ExpiringTree[T]) clock() time.Time {
+	if t.now == nil {
+		return time.Now()
+	}
+	return t.now()
+}
+
+// unindex removes the entry from the expiry heap, if it is there.
+func (t *ExpiringTree[T]) unindex(e *expiringEntry[T]) {
+	if e.index != -1 {
+		heap.Remove(&t.expiry, e.index)
+	}
+}
+
+func (e *expiringEntry[T]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// expiryHeap orders entries by expiry time, and keeps the index of each entry
+// up to date so that it can be removed when its value is replaced or deleted.
+type expiryHeap[T any] []*expiringEntry[T]
+
+// countExpired returns the number of expired entries in the heap at or below
+// index i. Only expired entries and their children are visited.
+func (h expiryHeap[T]) countExpired(i int, now time.Time) int {
+	if i >= len(h) || !h[i].expired(now) {
+		return 0
+	}
+	return 1 + h.countExpired(2*i+1, now) + h.countExpired(2*i+2, now)
+}
+
+func (h expiryHeap[T]) Len() int { return len(h) }
+
+func (h expiryHeap[T]) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h expiryHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap[T]) Push(x any) {
+	e := x.(*expiringEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap[T]) Pop() any {
+	old := *h
+	n := len(old) - 1
+	e := old[n]
+	old[n] = nil
+	e.index = -1
+	*h = old[:n]
+	return e
+}
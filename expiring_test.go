@@ -0,0 +1,269 @@
+package radixtree
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestExpiringTree(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	et := NewExpiringTree[int]()
+	et.SetClock(clock.Now)
+	var evicted []string
+	et.SetEvictFunc(func(key string, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	et.Put("user/1", 1)
+	et.PutWithTTL("user/2", 2, time.Minute)
+	et.PutWithTTL("user/3", 3, 2*time.Minute)
+	et.PutWithTTL("user/4", 4, 3*time.Minute)
+	et.PutWithTTL("user", 5, time.Minute)
+
+	keys := func() []string {
+		var out []string
+		for key := range et.IterAt("user/") {
+			out = append(out, key)
+		}
+		return out
+	}
+	if got := keys(); len(got) != 4 {
+		t.Fatalf("expected 4 keys, got %q", got)
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := et.Get("user/2"); ok {
+		t.Fatal("expected user/2 to be expired")
+	}
+	// Accessing an expired value removes only that value.
+	if want := []string{"user/2"}; !slices.Equal(evicted, want) {
+		t.Fatalf("expected %q evicted, got %q", want, evicted)
+	}
+	// Len does not count the expired value that is still stored.
+	if et.Len() != 3 || et.tree.Len() != 4 {
+		t.Fatalf("expected 3 of 4 stored values, got %d", et.Len())
+	}
+	if v, ok := et.Get("user/1"); !ok || v != 1 {
+		t.Fatal("expected user/1 to not expire")
+	}
+	if got, want := keys(), []string{"user/1", "user/3", "user/4"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Replacing a value resets its expiry, and a value with no TTL never
+	// expires.
+	et.PutWithTTL("user/3", 33, time.Hour)
+	et.Put("user/4", 44)
+	clock.Advance(30 * time.Minute)
+	evicted = nil
+	if n := et.Sweep(); n != 1 || evicted[0] != "user" {
+		t.Fatalf("expected only user swept, got %d %q", n, evicted)
+	}
+
+	// Iteration hides expired values and removes them.
+	et.PutWithTTL("user", 5, time.Minute)
+	clock.Advance(time.Minute)
+	if et.tree.Len() != 4 {
+		t.Fatalf("expected 4 stored values, got %d", et.tree.Len())
+	}
+	evicted = nil
+	for key := range et.IterPath("user/3") {
+		if key == "user" {
+			t.Fatal("expected user to be hidden from IterPath")
+		}
+	}
+	if et.tree.Len() != 3 || len(evicted) != 1 || evicted[0] != "user" {
+		t.Fatalf("expected user to be evicted by iteration, got %q", evicted)
+	}
+
+	// Deleted values are removed from the expiry index.
+	et.PutWithTTL("user/5", 5, time.Minute)
+	et.PutWithTTL("user/6", 6, time.Minute)
+	et.Delete("user/5")
+	et.DeletePrefix("user/3")
+	clock.Advance(time.Hour)
+	evicted = nil
+	if n := et.Sweep(); n != 1 || evicted[0] != "user/6" {
+		t.Fatalf("expected only user/6 swept, got %d %q", n, evicted)
+	}
+	if got, want := keys(), []string{"user/1", "user/4"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if len(et.expiry) != 0 {
+		t.Fatalf("expected empty expiry index, got %d entries", len(et.expiry))
+	}
+}
+
+func TestExpiringTreePutExpired(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	et := NewExpiringTree[int]()
+	et.SetClock(clock.Now)
+	var evicted []int
+	et.SetEvictFunc(func(_ string, value int) {
+		evicted = append(evicted, value)
+	})
+
+	if !et.PutWithTTL("k", 1, time.Minute) {
+		t.Fatal("expected new value")
+	}
+	if et.PutWithTTL("k", 2, time.Minute) || len(evicted) != 0 {
+		t.Fatal("expected unexpired value to be replaced without eviction")
+	}
+	clock.Advance(time.Minute)
+	if et.Len() != 0 {
+		t.Fatalf("expected no unexpired values, got %d", et.Len())
+	}
+	// Putting over an expired value that was not removed adds a new value and
+	// evicts the expired one.
+	if !et.PutWithTTL("k", 3, time.Minute) {
+		t.Fatal("expected put over expired value to add a new value")
+	}
+	if !slices.Equal(evicted, []int{2}) {
+		t.Fatalf("expected expired value 2 evicted, got %v", evicted)
+	}
+	clock.Advance(time.Minute)
+	if !et.Put("k", 4) || !slices.Equal(evicted, []int{2, 3}) {
+		t.Fatalf("expected expired value 3 evicted, got %v", evicted)
+	}
+	if v, ok := et.Get("k"); !ok || v != 4 || et.Len() != 1 || len(et.expiry) != 0 {
+		t.Fatal("expected only unexpiring value 4")
+	}
+}
+
+func TestExpiringTreeDeletePrefixEmpty(t *testing.T) {
+	et := NewExpiringTree[int]()
+	if et.DeletePrefix("") || et.Len() != 0 {
+		t.Fatalf("DeletePrefix on empty tree removed values, length %d", et.Len())
+	}
+	et.PutWithTTL("a", 1, time.Minute)
+	et.Put("b", 2)
+	if !et.DeletePrefix("") || et.Len() != 0 || len(et.expiry) != 0 {
+		t.Fatal("DeletePrefix did not remove all values")
+	}
+	if et.DeletePrefix("") || et.Len() != 0 {
+		t.Fatalf("DeletePrefix on emptied tree removed values, length %d", et.Len())
+	}
+}
+
+func TestExpiringTreeIterBatches(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	et := NewExpiringTree[int]()
+	et.SetClock(clock.Now)
+	var evicted int
+	et.SetEvictFunc(func(string, int) {
+		evicted++
+	})
+	var want []string
+	for i := range 1000 {
+		key := fmt.Sprintf("k/%03d", i)
+		if i%3 == 0 {
+			et.PutWithTTL(key, i, time.Minute)
+			continue
+		}
+		et.Put(key, i)
+		want = append(want, key)
+	}
+	clock.Advance(time.Minute)
+	if et.Len() != len(want) {
+		t.Fatalf("expected %d values, got %d", len(want), et.Len())
+	}
+
+	// Stopping early only removes the expired values visited in the first
+	// batch.
+	for range et.IterAt("k/") {
+		break
+	}
+	if evicted == 0 || evicted > expiringBatchSize {
+		t.Fatalf("expected at most one batch evicted, got %d", evicted)
+	}
+
+	var got []string
+	for key, val := range et.Iter() {
+		if key != fmt.Sprintf("k/%03d", val) {
+			t.Fatalf("wrong value %d for key %q", val, key)
+		}
+		got = append(got, key)
+		// Values put behind the iteration are not visited.
+		et.Put("a"+key, val)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %d keys in order, got %d", len(want), len(got))
+	}
+	if evicted != 334 || len(et.expiry) != 0 {
+		t.Fatalf("expected all expired values evicted, got %d", evicted)
+	}
+	if et.Len() != 2*len(want) {
+		t.Fatalf("expected %d values, got %d", 2*len(want), et.Len())
+	}
+}
+
+func TestExpiringTreeIterConcurrent(t *testing.T) {
+	et := NewExpiringTree[int]()
+	et.Put("a", 1)
+	et.Put("b", 2)
+
+	// The loop body may call methods of the tree, while another goroutine is
+	// waiting to modify it.
+	for key := range et.Iter() {
+		done := make(chan struct{})
+		go func() {
+			et.Put(key+"x", 0)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for Put during iteration")
+		}
+		if _, ok := et.Get(key); !ok {
+			t.Fatalf("expected %q in tree", key)
+		}
+	}
+	if et.Len() != 4 {
+		t.Fatalf("expected 4 values, got %d", et.Len())
+	}
+}
+
+func TestExpiringTreeSweeper(t *testing.T) {
+	et := NewExpiringTree[int]()
+	evicted := make(chan string, 1)
+	et.SetEvictFunc(func(key string, _ int) {
+		evicted <- key
+	})
+	et.PutWithTTL("a", 1, time.Millisecond)
+	stop := et.StartSweeper(time.Millisecond)
+	defer stop()
+
+	select {
+	case key := <-evicted:
+		if key != "a" {
+			t.Fatalf("expected a evicted, got %q", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for sweeper")
+	}
+	if et.Len() != 0 {
+		t.Fatal("expected tree to be empty")
+	}
+	stop()
+}
@@ -0,0 +1,108 @@
+package radixtree
+
+import (
+	"iter"
+)
+
+// IterFuzzy visits all nodes whose keys are within maxDist Levenshtein edit
+// distance of the query, yielding the key and value of each. The distance is
+// measured in bytes, with insertion, deletion, and substitution each costing
+// one.
+//
+// The tree is walked once, keeping one row of the edit distance matrix for
+// each byte of key traversed. Subtrees are skipped as soon as every entry in
+// the row exceeds maxDist, since no key below can then be close enough.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *Tree[T]) IterFuzzy(query string, maxDist int) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for item := range t.IterFuzzyDist(query, maxDist) {
+			if !yield(item.key, item.value) {
+				return
+			}
+		}
+	}
+}
+
+// IterFuzzyDist is the same as IterFuzzy, but yields the Item and its edit
+// distance from the query.
+func (t *Tree[T]) IterFuzzyDist(query string, maxDist int) iter.Seq2[*Item[T], int] {
+	return func(yield func(*Item[T], int) bool) {
+		if maxDist < 0 {
+			return
+		}
+		f := fuzzyWalk[T]{
+			query: query,
+			max:   maxDist,
+			width: len(query) + 1,
+			yield: checkedYield(t, yield),
+		}
+		f.rows = make([]int, f.width, f.width*8)
+		for i := range f.width {
+			f.rows[i] = i
+		}
+		f.walk(&t.root, 0)
+	}
+}
+
+// fuzzyWalk holds the state for a fuzzy search. The rows of the edit distance
+// matrix are stored contiguously in rows, with row d holding the distances
+// between each prefix of the query and the first d bytes of the current key.
+type fuzzyWalk[T any] struct {
+	query string
+	max   int
+	width int
+	rows  []int
+	yield func(*Item[T], int) bool
+}
+
+// walk visits the node, whose edge radix has been consumed to arrive at the
+// given depth, and its children. Returns false if iteration was stopped.
+func (f *fuzzyWalk[T]) walk(node *radixNode[T], depth int) bool {
+	for i := 0; i < len(node.prefix); i++ {
+		if f.step(depth, node.prefix[i]) > f.max {
+			return true
+		}
+		depth++
+	}
+	if node.leaf != nil {
+		if dist := f.rows[depth*f.width+f.width-1]; dist <= f.max {
+			if !f.yield(node.leaf, dist) {
+				return false
+			}
+		}
+	}
+	for i, child := range node.nodes {
+		if f.step(depth, node.radices[i]) > f.max {
+			continue
+		}
+		if !f.walk(child, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// step computes the row at depth+1 from the row at depth, for the key byte b,
+// and returns the minimum distance in the new row.
+func (f *fuzzyWalk[T]) step(depth int, b byte) int {
+	start := (depth + 1) * f.width
+	if len(f.rows) < start+f.width {
+		f.rows = append(f.rows[:start], make([]int, f.width)...)
+	}
+	prev := f.rows[depth*f.width : start]
+	row := f.rows[start : start+f.width]
+
+	row[0] = prev[0] + 1
+	minDist := row[0]
+	for i := 1; i < f.width; i++ {
+		cost := 1
+		if f.query[i-1] == b {
+			cost = 0
+		}
+		// Substitution, insertion, or deletion.
+		row[i] = min(prev[i-1]+cost, row[i-1]+1, prev[i]+1)
+		minDist = min(minDist, row[i])
+	}
+	return minDist
+}
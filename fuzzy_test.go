@@ -0,0 +1,87 @@
+package radixtree
+
+import (
+	"testing"
+)
+
+func TestIterFuzzy(t *testing.T) {
+	words := []string{
+		"", "a", "book", "books", "boo", "boon", "cook", "cake", "back",
+		"tom", "tomato", "tommy", "torn", "tornado", "brook", "bookkeeper",
+	}
+	rt := New[int]()
+	for i, w := range words {
+		rt.Put(w, i)
+	}
+
+	for _, query := range []string{"book", "tomatoe", "", "bk", "xyz"} {
+		for maxDist := 0; maxDist <= 3; maxDist++ {
+			found := map[string]int{}
+			var prev string
+			for item, dist := range rt.IterFuzzyDist(query, maxDist) {
+				if len(found) != 0 && item.Key() <= prev {
+					t.Fatalf("keys out of order: %q after %q", item.Key(), prev)
+				}
+				prev = item.Key()
+				found[item.Key()] = dist
+			}
+			for _, w := range words {
+				want := levenshtein(query, w)
+				dist, ok := found[w]
+				if want <= maxDist {
+					if !ok {
+						t.Errorf("query %q dist %d: missing %q", query, maxDist, w)
+					} else if dist != want {
+						t.Errorf("query %q: expected distance %d to %q, got %d", query, want, w, dist)
+					}
+				} else if ok {
+					t.Errorf("query %q dist %d: unexpected %q", query, maxDist, w)
+				}
+			}
+		}
+	}
+
+	var keys []string
+	for k, v := range rt.IterFuzzy("tomato", 1) {
+		if words[v] != k {
+			t.Fatalf("wrong value %d for key %q", v, k)
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) != 1 || keys[0] != "tomato" {
+		t.Fatal("expected only \"tomato\", got", keys)
+	}
+
+	for range rt.IterFuzzy("book", -1) {
+		t.Fatal("expected nothing for negative distance")
+	}
+
+	var count int
+	for range rt.IterFuzzy("book", 2) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	row := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min(prev[j-1]+cost, row[j-1]+1, prev[j]+1)
+		}
+		prev, row = row, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,293 @@
+package radixtree
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// IterGlob visits all nodes whose keys match the glob pattern, yielding the key
+// and value of each. Pattern syntax is:
+//
+//	'*'         matches any sequence of non-'/' bytes
+//	'**'        matches any sequence of bytes, including '/'
+//	'**/'       matches zero or more complete '/'-terminated segments
+//	'?'         matches any single non-'/' byte
+//	'[' class ']'
+//	            matches any single non-'/' byte in class
+//	'\\' c      matches byte c
+//
+// A class is a set of bytes and ranges such as "[a-z0-9_]", and is negated if
+// it begins with '!' or '^'. A ']' immediately after the opening '[' or
+// negation is part of the class. A '[' with no closing ']' matches itself.
+//
+// The tree is walked once, tracking the set of pattern positions reachable by
+// the key traversed so far. Literal parts of the pattern are followed by edge
+// lookup, so the walk branches only at wildcard positions, and subtrees are
+// skipped as soon as no pattern position remains.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *Tree[T]) IterGlob(pattern string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		g := newGlobWalk(compileGlob(pattern), checkedYield(t, yield))
+		g.walk(&t.root, 0)
+	}
+}
+
+type globOp uint8
+
+const (
+	globByte        globOp = iota // literal byte
+	globAny                       // '?'
+	globClass                     // '[...]'
+	globStar                      // '*'
+	globStarStar                  // '**'
+	globStarStarSep               // '**/', followed by a globByte '/'
+)
+
+const globSep = '/'
+
+type globToken struct {
+	op    globOp
+	b     byte
+	class [4]uint64
+}
+
+func (tok *globToken) inClass(b byte) bool {
+	return tok.class[b>>6]&(1<<(b&63)) != 0
+}
+
+// compileGlob converts the pattern into a sequence of tokens.
+func compileGlob(pattern string) []globToken {
+	var toks []globToken
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 == len(pattern) || pattern[i+1] != '*' {
+				toks = append(toks, globToken{op: globStar})
+				continue
+			}
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == globSep {
+				toks = append(toks, globToken{op: globStarStarSep})
+			} else {
+				toks = append(toks, globToken{op: globStarStar})
+			}
+		case '?':
+			toks = append(toks, globToken{op: globAny})
+		case '[':
+			tok := globToken{op: globClass}
+			if n := parseGlobClass(pattern[i:], &tok.class); n != 0 {
+				toks = append(toks, tok)
+				i += n - 1
+			} else {
+				toks = append(toks, globToken{op: globByte, b: c})
+			}
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				c = pattern[i]
+			}
+			toks = append(toks, globToken{op: globByte, b: c})
+		default:
+			toks = append(toks, globToken{op: globByte, b: c})
+		}
+	}
+	return toks
+}
+
+// parseGlobClass parses the class at the start of s, which begins with '[',
+// into class. Returns the number of bytes parsed, or 0 if the class is not
+// terminated.
+func parseGlobClass(s string, class *[4]uint64) int {
+	i := 1
+	var negate bool
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		negate = true
+		i++
+	}
+	for first := true; i < len(s); first = false {
+		c := s[i]
+		if c == ']' && !first {
+			if negate {
+				for j := range class {
+					class[j] = ^class[j]
+				}
+			}
+			return i + 1
+		}
+		if c == '\\' && i+1 < len(s) {
+			i++
+			c = s[i]
+		}
+		i++
+		hi := c
+		if i+1 < len(s) && s[i] == '-' && s[i+1] != ']' {
+			hi = s[i+1]
+			i += 2
+			if hi == '\\' && i < len(s) {
+				hi = s[i]
+				i++
+			}
+		}
+		for b := int(c); b <= int(hi); b++ {
+			class[b>>6] |= 1 << (b & 63)
+		}
+	}
+	return 0
+}
+
+// globWalk holds the state for a glob search. Sets of pattern positions are
+// stored as bitsets contiguously in sets, with set d holding the positions
+// reachable after the first d bytes of the current key.
+type globWalk[T any] struct {
+	toks  []globToken
+	width int
+	sets  []uint64
+	yield func(string, T) bool
+}
+
+func newGlobWalk[T any](toks []globToken, yield func(string, T) bool) *globWalk[T] {
+	g := &globWalk[T]{
+		toks:  toks,
+		width: (len(toks) + 64) / 64,
+		yield: yield,
+	}
+	g.sets = make([]uint64, g.width, g.width*8)
+	g.closure(g.sets, 0)
+	return g
+}
+
+// walk visits the node, whose edge radix has been consumed to arrive at the
+// given depth, and its children. Returns false if iteration was stopped.
+func (g *globWalk[T]) walk(node *radixNode[T], depth int) bool {
+	for i := 0; i < len(node.prefix); i++ {
+		if !g.step(depth, node.prefix[i]) {
+			return true
+		}
+		depth++
+	}
+	set := g.sets[depth*g.width : (depth+1)*g.width]
+	accept := len(g.toks)
+	if node.leaf != nil && set[accept>>6]&(1<<(accept&63)) != 0 {
+		if !g.yield(node.leaf.key, node.leaf.value) {
+			return false
+		}
+	}
+	if len(node.radices) == 0 {
+		return true
+	}
+
+	// If the only pattern position, other than the accepting one, is a
+	// literal byte, then follow that edge only.
+	var count, pos int
+	for w, word := range set {
+		for word != 0 {
+			i := w*64 + bits.TrailingZeros64(word)
+			word &= word - 1
+			if i != accept {
+				count++
+				pos = i
+			}
+		}
+	}
+	if count == 0 {
+		return true
+	}
+	if count == 1 && g.toks[pos].op == globByte {
+		radix := g.toks[pos].b
+		child := node.getEdge(radix)
+		if child == nil || !g.step(depth, radix) {
+			return true
+		}
+		return g.walk(child, depth+1)
+	}
+
+	for i, child := range node.nodes {
+		if !g.step(depth, node.radices[i]) {
+			continue
+		}
+		if !g.walk(child, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// step computes the set at depth+1 from the set at depth, for the key byte b.
+// Returns false if the new set is empty.
+func (g *globWalk[T]) step(depth int, b byte) bool {
+	start := (depth + 1) * g.width
+	if len(g.sets) < start+g.width {
+		g.sets = append(g.sets[:start], make([]uint64, g.width)...)
+	}
+	cur := g.sets[depth*g.width : start]
+	next := g.sets[start : start+g.width]
+	clear(next)
+
+	for w, word := range cur {
+		for word != 0 {
+			i := w*64 + bits.TrailingZeros64(word)
+			word &= word - 1
+			if i == len(g.toks) {
+				continue
+			}
+			tok := &g.toks[i]
+			switch tok.op {
+			case globByte:
+				if b == tok.b {
+					g.closure(next, i+1)
+				}
+			case globAny:
+				if b != globSep {
+					g.closure(next, i+1)
+				}
+			case globClass:
+				if b != globSep && tok.inClass(b) {
+					g.closure(next, i+1)
+				}
+			case globStar:
+				if b != globSep {
+					g.closure(next, i)
+				}
+			case globStarStar:
+				g.closure(next, i)
+			case globStarStarSep:
+				// Remain within the segments, but do not skip the
+				// following separator since this segment is not empty.
+				// Positions are visited in ascending order, so any
+				// closure entering i has already been added.
+				next[i>>6] |= 1 << (i & 63)
+				g.closure(next, i+1)
+			}
+		}
+	}
+	for _, word := range next {
+		if word != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// closure adds pattern position i to the set, along with all positions
+// reachable from it without consuming any bytes.
+func (g *globWalk[T]) closure(set []uint64, i int) {
+	if set[i>>6]&(1<<(i&63)) != 0 {
+		return
+	}
+	set[i>>6] |= 1 << (i & 63)
+	if i == len(g.toks) {
+		return
+	}
+	switch g.toks[i].op {
+	case globStar, globStarStar:
+		g.closure(set, i+1)
+	case globStarStarSep:
+		g.closure(set, i+1)
+		// Match zero segments by skipping the following separator.
+		g.closure(set, i+2)
+	}
+}
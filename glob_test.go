@@ -0,0 +1,85 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIterGlob(t *testing.T) {
+	keys := []string{
+		"logs/a/2024-01.json",
+		"logs/a/2024-02.json",
+		"logs/a/2024-02.txt",
+		"logs/b/2024-11.json",
+		"logs/b/c/2024-12.json",
+		"logs/2024-01.json",
+		"data/x.json",
+		"data/[x].json",
+		"a*b",
+		"axb",
+	}
+	rt := New[int]()
+	for i, k := range keys {
+		rt.Put(k, i)
+	}
+
+	tests := []struct {
+		pattern string
+		expect  []string
+	}{
+		{"logs/*/2024-??.json", []string{
+			"logs/a/2024-01.json", "logs/a/2024-02.json", "logs/b/2024-11.json"}},
+		{"logs/*", []string{"logs/2024-01.json"}},
+		{"logs/**", []string{
+			"logs/2024-01.json", "logs/a/2024-01.json", "logs/a/2024-02.json",
+			"logs/a/2024-02.txt", "logs/b/2024-11.json", "logs/b/c/2024-12.json"}},
+		{"logs/**/2024-1?.json", []string{
+			"logs/b/2024-11.json", "logs/b/c/2024-12.json"}},
+		{"logs/**/2024-01.json", []string{
+			"logs/2024-01.json", "logs/a/2024-01.json"}},
+		{"**.txt", []string{"logs/a/2024-02.txt"}},
+		{"*.txt", nil},
+		{"logs/[ab]/*-0[!1].*", []string{"logs/a/2024-02.json", "logs/a/2024-02.txt"}},
+		{"logs/[^a]/*", []string{"logs/b/2024-11.json"}},
+		{"logs/[a-b]/c/*", []string{"logs/b/c/2024-12.json"}},
+		{"data/\\[x].json", []string{"data/[x].json"}},
+		{"data/[[]x].json", []string{"data/[x].json"}},
+		{"data/[x.json", nil},
+		{"a\\*b", []string{"a*b"}},
+		{"a?b", []string{"a*b", "axb"}},
+		{"a[]]b", nil},
+		{"logs/a/2024-01.json", []string{"logs/a/2024-01.json"}},
+		{"logs/a/2024-01", nil},
+		{"", nil},
+	}
+	for _, tc := range tests {
+		var found []string
+		for k, v := range rt.IterGlob(tc.pattern) {
+			if keys[v] != k {
+				t.Fatalf("wrong value %d for key %q", v, k)
+			}
+			found = append(found, k)
+		}
+		if !slices.Equal(found, tc.expect) {
+			t.Errorf("pattern %q: expected %q, got %q", tc.pattern, tc.expect, found)
+		}
+	}
+
+	rt.Put("", -1)
+	var count int
+	for range rt.IterGlob("") {
+		count++
+	}
+	if count != 1 {
+		t.Fatal("expected empty pattern to match empty key")
+	}
+
+	count = 0
+	for range rt.IterGlob("**") {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+}
@@ -0,0 +1,212 @@
+package radixtree
+
+import (
+	"io"
+	"iter"
+)
+
+// Matcher finds every occurrence of the keys of a tree within input data,
+// using the Aho-Corasick algorithm. The input is scanned in a single pass, and
+// all matches are reported, including those that overlap.
+//
+// A Matcher is built from a snapshot of the tree, and is not affected by later
+// modification of the tree. It is immutable, and may be used to scan any
+// number of inputs concurrently.
+type Matcher[T any] struct {
+	states []matchState[T]
+}
+
+// matchState is a position in the tree. There is one state for each byte of
+// each edge, so the states of a node with prefix "abc" represent the positions
+// before consuming 'a', 'b', and 'c', and the position at the node's value.
+type matchState[T any] struct {
+	// radices and next are the bytes that advance from this state, and the
+	// states they lead to. Within a node's prefix there is a single byte.
+	radices []byte
+	next    []int32
+	// fail is the state for the longest proper suffix, of the key consumed to
+	// reach this state, that is also a position in the tree.
+	fail int32
+	// dict is the next state along the chain of fail links that has an item,
+	// or -1 if there is none.
+	dict int32
+	item *Item[T]
+}
+
+// NewMatcher builds a Matcher that finds occurrences of all keys in the tree.
+// The empty key, if present, is never matched.
+func (t *Tree[T]) NewMatcher() *Matcher[T] {
+	// Count states so that all transitions can share backing arrays.
+	count := t.root.countStates()
+	m := &Matcher[T]{
+		states: make([]matchState[T], count),
+	}
+	radices := make([]byte, 0, count-1)
+	next := make([]int32, 0, count-1)
+
+	// Assign states to nodes in breadth-first order. The states of a node are
+	// consecutive, starting at the node's base.
+	type queued struct {
+		node *radixNode[T]
+		base int32
+	}
+	queue := []queued{{&t.root, 0}}
+	free := int32(len(t.root.prefix) + 1)
+	for len(queue) != 0 {
+		q := queue[0]
+		queue = queue[1:]
+		node := q.node
+		s := q.base
+		for i := 0; i < len(node.prefix); i++ {
+			radices = append(radices, node.prefix[i])
+			next = append(next, s+1)
+			m.states[s].radices = radices[len(radices)-1:]
+			m.states[s].next = next[len(next)-1:]
+			s++
+		}
+		start := len(next)
+		for _, child := range node.nodes {
+			queue = append(queue, queued{child, free})
+			next = append(next, free)
+			free += int32(len(child.prefix) + 1)
+		}
+		radices = append(radices, node.radices...)
+		m.states[s].radices = radices[start:len(radices):len(radices)]
+		m.states[s].next = next[start:len(next):len(next)]
+		if node != &t.root {
+			m.states[s].item = node.leaf
+		}
+	}
+
+	// Compute fail links in breadth-first order of key length, so that the
+	// fail link of every shorter key is known before it is needed.
+	m.states[0].dict = -1
+	order := make([]int32, 1, count)
+	for i := 0; i < len(order); i++ {
+		s := order[i]
+		st := &m.states[s]
+		for j, b := range st.radices {
+			child := st.next[j]
+			order = append(order, child)
+			var fail int32
+			if s != 0 {
+				fail = m.delta(st.fail, b)
+			}
+			cs := &m.states[child]
+			cs.fail = fail
+			if m.states[fail].item != nil {
+				cs.dict = fail
+			} else {
+				cs.dict = m.states[fail].dict
+			}
+		}
+	}
+	return m
+}
+
+// Match visits every occurrence of a key within data, yielding the offset in
+// data at which the key starts and the Item with the key and its value.
+// Matches are yielded in order of the offset at which they end, and longest
+// first for matches that end at the same offset.
+func (m *Matcher[T]) Match(data []byte) iter.Seq2[int, *Item[T]] {
+	return func(yield func(int, *Item[T]) bool) {
+		scanMatches(m, 0, data, 0, yield)
+	}
+}
+
+// MatchString is the same as Match, but scans a string.
+func (m *Matcher[T]) MatchString(s string) iter.Seq2[int, *Item[T]] {
+	return func(yield func(int, *Item[T]) bool) {
+		scanMatches(m, 0, s, 0, yield)
+	}
+}
+
+// MatchReader scans all data read from r, calling fn for every occurrence of a
+// key with the offset in the data at which the key starts and the Item with
+// the key and its value. Matches that span separate reads are found. If fn
+// returns false, then scanning stops.
+//
+// Returns any error from r other than io.EOF.
+func (m *Matcher[T]) MatchReader(r io.Reader, fn func(offset int64, item *Item[T]) bool) error {
+	buf := make([]byte, 32*1024)
+	var (
+		s   int32
+		off int64
+	)
+	for {
+		n, err := r.Read(buf)
+		if s = scanMatches(m, s, buf[:n], off, fn); s == -1 {
+			return nil
+		}
+		off += int64(n)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// scanMatches scans data, starting from the given state, and yields the offset
+// of each match relative to base. Returns the state at the end of data, or -1
+// if yield returned false.
+func scanMatches[T any, D ~string | ~[]byte, O int | int64](m *Matcher[T], s int32, data D, base O, yield func(O, *Item[T]) bool) int32 {
+	for i := 0; i < len(data); i++ {
+		s = m.delta(s, data[i])
+		end := base + O(i+1)
+		if item := m.states[s].item; item != nil && !yield(end-O(len(item.key)), item) {
+			return -1
+		}
+		for d := m.states[s].dict; d != -1; d = m.states[d].dict {
+			item := m.states[d].item
+			if !yield(end-O(len(item.key)), item) {
+				return -1
+			}
+		}
+	}
+	return s
+}
+
+// delta returns the state reached by consuming b in state s, following fail
+// links until a state that can consume b is found.
+func (m *Matcher[T]) delta(s int32, b byte) int32 {
+	for {
+		if next := m.states[s].step(b); next != -1 {
+			return next
+		}
+		if s == 0 {
+			return 0
+		}
+		s = m.states[s].fail
+	}
+}
+
+// step returns the state reached by consuming b, or -1 if b does not advance
+// from this state.
+func (st *matchState[T]) step(b byte) int32 {
+	// Binary search, as in indexEdge.
+	i, j := 0, len(st.radices)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if st.radices[h] < b {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(st.radices) && st.radices[i] == b {
+		return st.next[i]
+	}
+	return -1
+}
+
+// countStates returns the number of Matcher states for the node and its
+// descendants.
+func (node *radixNode[T]) countStates() int {
+	count := len(node.prefix) + 1
+	for _, child := range node.nodes {
+		count += child.countStates()
+	}
+	return count
+}
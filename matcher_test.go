@@ -0,0 +1,136 @@
+package radixtree
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type matchResult struct {
+	offset int
+	key    string
+}
+
+func TestMatcher(t *testing.T) {
+	keys := []string{"he", "she", "his", "hers", "h", "error", "err", "rror:", "a", "aa", ""}
+	rt := New[int]()
+	for i, k := range keys {
+		rt.Put(k, i)
+	}
+	m := rt.NewMatcher()
+
+	inputs := []string{
+		"ushers",
+		"his error: aaa she said",
+		"",
+		"xyz",
+		"hhhhershe",
+	}
+	for _, input := range inputs {
+		expect := bruteMatches(keys, input)
+		var found []matchResult
+		for off, item := range m.MatchString(input) {
+			if keys[item.Value()] != item.Key() {
+				t.Fatalf("wrong value %d for key %q", item.Value(), item.Key())
+			}
+			found = append(found, matchResult{off, item.Key()})
+		}
+		sortMatches(found)
+		if !slices.Equal(found, expect) {
+			t.Errorf("input %q: expected %v, got %v", input, expect, found)
+		}
+
+		found = found[:0]
+		for off, item := range m.Match([]byte(input)) {
+			found = append(found, matchResult{off, item.Key()})
+		}
+		sortMatches(found)
+		if !slices.Equal(found, expect) {
+			t.Errorf("input %q: expected %v from Match, got %v", input, expect, found)
+		}
+
+		// Read one byte at a time so that matches span reads.
+		found = found[:0]
+		err := m.MatchReader(iotest.OneByteReader(strings.NewReader(input)), func(off int64, item *Item[int]) bool {
+			found = append(found, matchResult{int(off), item.Key()})
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortMatches(found)
+		if !slices.Equal(found, expect) {
+			t.Errorf("input %q: expected %v from MatchReader, got %v", input, expect, found)
+		}
+	}
+
+	var count int
+	for range m.MatchString("ushers") {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+	count = 0
+	err := m.MatchReader(strings.NewReader("ushers"), func(int64, *Item[int]) bool {
+		count++
+		return false
+	})
+	if err != nil || count != 1 {
+		t.Fatal("scanning did not stop")
+	}
+
+	errRead := errors.New("read error")
+	err = m.MatchReader(iotest.ErrReader(errRead), func(int64, *Item[int]) bool {
+		return true
+	})
+	if !errors.Is(err, errRead) {
+		t.Fatal("expected read error, got", err)
+	}
+
+	// Matcher is not affected by modifying the tree.
+	rt.Delete("she")
+	rt.Put("us", 99)
+	count = 0
+	for _, item := range m.MatchString("ushers") {
+		if item.Key() == "us" {
+			t.Fatal("matcher affected by tree modification")
+		}
+		count++
+	}
+	if count != 4 {
+		t.Fatalf("expected 4 matches, got %d", count)
+	}
+
+	for range New[int]().NewMatcher().MatchString("abc") {
+		t.Fatal("empty matcher should not match")
+	}
+}
+
+func bruteMatches(keys []string, input string) []matchResult {
+	var found []matchResult
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		for i := 0; i+len(k) <= len(input); i++ {
+			if input[i:i+len(k)] == k {
+				found = append(found, matchResult{i, k})
+			}
+		}
+	}
+	sortMatches(found)
+	return found
+}
+
+func sortMatches(m []matchResult) {
+	slices.SortFunc(m, func(a, b matchResult) int {
+		if a.offset != b.offset {
+			return a.offset - b.offset
+		}
+		return strings.Compare(a.key, b.key)
+	})
+}
@@ -0,0 +1,140 @@
+package radixtree
+
+import (
+	"iter"
+	"slices"
+)
+
+// MultiTree is a radix tree that stores any number of values for each key,
+// such as an inverted index that maps each term to many documents. The values
+// of each key are kept in the order that they were added, and a value may be
+// added more than once for the same key.
+type MultiTree[T comparable] struct {
+	tree Tree[[]T]
+	size int
+}
+
+// NewMultiTree creates a new radix tree with multiple values per key.
+func NewMultiTree[T comparable]() *MultiTree[T] {
+	return new(MultiTree[T])
+}
+
+// Len returns the total number of values stored in the tree, counting each
+// value of each key.
+func (t *MultiTree[T]) Len() int {
+	return t.size
+}
+
+// KeyLen returns the number of keys that have at least one value.
+func (t *MultiTree[T]) KeyLen() int {
+	return t.tree.Len()
+}
+
+// CountKey returns the number of values stored for the key.
+func (t *MultiTree[T]) CountKey(key string) int {
+	vals, _ := t.tree.Get(key)
+	return len(vals)
+}
+
+// Add appends the value to the values of the key.
+func (t *MultiTree[T]) Add(key string, value T) {
+	if item := t.item(key); item != nil {
+		item.value = append(item.value, value)
+	} else {
+		t.tree.Put(key, []T{value})
+	}
+	t.size++
+}
+
+// Has returns true if the value is stored for the key.
+func (t *MultiTree[T]) Has(key string, value T) bool {
+	vals, _ := t.tree.Get(key)
+	return slices.Contains(vals, value)
+}
+
+// Remove removes the first occurrence of the value from the values of the key.
+// If the key has no values remaining, then the key is removed. Returns true if
+// the value was stored for the key.
+func (t *MultiTree[T]) Remove(key string, value T) bool {
+	item := t.item(key)
+	if item == nil {
+		return false
+	}
+	i := slices.Index(item.value, value)
+	if i == -1 {
+		return false
+	}
+	if len(item.value) == 1 {
+		t.tree.Delete(key)
+	} else {
+		// Build a new slice, so that a running iteration over the old one
+		// does not see its values shifted.
+		item.value = slices.Concat(item.value[:i:i], item.value[i+1:])
+	}
+	t.size--
+	return true
+}
+
+// RemoveKey removes the key and all of its values. Returns the number of
+// values removed.
+func (t *MultiTree[T]) RemoveKey(key string) int {
+	vals, _ := t.tree.Get(key)
+	if len(vals) == 0 {
+		return 0
+	}
+	t.tree.Delete(key)
+	t.size -= len(vals)
+	return len(vals)
+}
+
+// Values visits the values of the key, in the order that they were added.
+func (t *MultiTree[T]) Values(key string) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		vals, _ := t.tree.Get(key)
+		for _, v := range vals {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Iter visits all values in the tree, yielding the key and a value for each
+// value of each key.
+//
+// The tree is traversed in lexical order of keys, with the values of each key
+// in the order that they were added, making the output deterministic.
+func (t *MultiTree[T]) Iter() iter.Seq2[string, T] {
+	return multiPairs(t.tree.Iter())
+}
+
+// IterAt visits all values whose keys match or are prefixed by the specified
+// key, yielding the key and a value for each value of each key.
+//
+// The tree is traversed in lexical order of keys, with the values of each key
+// in the order that they were added, making the output deterministic.
+func (t *MultiTree[T]) IterAt(key string) iter.Seq2[string, T] {
+	return multiPairs(t.tree.IterAt(key))
+}
+
+// item returns the item stored at the key, so that its values can be updated
+// in place, or nil if there is no item.
+func (t *MultiTree[T]) item(key string) *Item[[]T] {
+	node, rem := t.tree.locate(key)
+	if node == nil || rem != "" {
+		return nil
+	}
+	return node.leaf
+}
+
+func multiPairs[T any](seq iter.Seq2[string, []T]) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for key, vals := range seq {
+			for _, v := range vals {
+				if !yield(key, v) {
+					return
+				}
+			}
+		}
+	}
+}
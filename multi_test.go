@@ -0,0 +1,103 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiTree(t *testing.T) {
+	mt := NewMultiTree[int]()
+	mt.Add("radix", 3)
+	mt.Add("radix", 1)
+	mt.Add("radix", 3)
+	mt.Add("rad", 2)
+	mt.Add("tree", 1)
+
+	if mt.Len() != 5 || mt.KeyLen() != 3 {
+		t.Fatalf("expected 5 values and 3 keys, got %d and %d", mt.Len(), mt.KeyLen())
+	}
+	if n := mt.CountKey("radix"); n != 3 {
+		t.Fatalf("expected 3 values for radix, got %d", n)
+	}
+	if got := slices.Collect(mt.Values("radix")); !slices.Equal(got, []int{3, 1, 3}) {
+		t.Fatalf("unexpected values %v", got)
+	}
+	if !mt.Has("rad", 2) || mt.Has("rad", 3) || mt.Has("ra", 2) {
+		t.Fatal("wrong result from Has")
+	}
+
+	type pair struct {
+		key string
+		val int
+	}
+	var got []pair
+	for key, val := range mt.IterAt("rad") {
+		got = append(got, pair{key, val})
+	}
+	want := []pair{{"rad", 2}, {"radix", 3}, {"radix", 1}, {"radix", 3}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if !mt.Remove("radix", 3) || mt.Remove("radix", 7) || mt.Remove("none", 3) {
+		t.Fatal("wrong result from Remove")
+	}
+	if vals := slices.Collect(mt.Values("radix")); !slices.Equal(vals, []int{1, 3}) {
+		t.Fatalf("unexpected values after Remove %v", vals)
+	}
+	if !mt.Remove("rad", 2) || mt.CountKey("rad") != 0 || mt.KeyLen() != 2 {
+		t.Fatal("key with no values should be removed")
+	}
+	if n := mt.RemoveKey("radix"); n != 2 {
+		t.Fatalf("expected 2 values removed, got %d", n)
+	}
+	if mt.Len() != 1 || mt.KeyLen() != 1 {
+		t.Fatalf("expected 1 value and 1 key, got %d and %d", mt.Len(), mt.KeyLen())
+	}
+	if n := mt.RemoveKey("radix"); n != 0 {
+		t.Fatalf("expected nothing removed, got %d", n)
+	}
+	var n int
+	for range mt.Iter() {
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 pair, got %d", n)
+	}
+}
+
+func TestMultiTreeRemoveDuringIter(t *testing.T) {
+	mt := NewMultiTree[int]()
+	mt.Add("k", 1)
+	mt.Add("k", 2)
+	mt.Add("k", 3)
+
+	var got []int
+	for v := range mt.Values("k") {
+		got = append(got, v)
+		mt.Remove("k", v)
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+	if mt.Len() != 0 || mt.KeyLen() != 0 {
+		t.Fatalf("expected empty tree, got %d values", mt.Len())
+	}
+
+	mt.Add("k", 1)
+	mt.Add("k", 2)
+	mt.Add("k", 3)
+	got = got[:0]
+	for _, v := range mt.Iter() {
+		got = append(got, v)
+		if v == 1 {
+			mt.Remove("k", 2)
+		}
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+	if vals := slices.Collect(mt.Values("k")); !slices.Equal(vals, []int{1, 3}) {
+		t.Fatalf("unexpected values after Remove %v", vals)
+	}
+}
@@ -0,0 +1,216 @@
+package radixtree
+
+import (
+	"iter"
+	"net/netip"
+)
+
+// PrefixTable is an IP routing table that maps IPv4 and IPv6 network prefixes
+// to values. Prefixes may have any length, including lengths that do not end
+// on a byte boundary such as /13 or /27.
+//
+// IPv4 and IPv6 prefixes are stored separately. An IPv4-mapped IPv6 prefix of
+// at least 96 bits, such as ::ffff:10.0.0.0/104, is stored and queried as the
+// equivalent IPv4 prefix, such as 10.0.0.0/8. An IPv4-mapped address or prefix
+// is contained by both the IPv4 prefixes that contain its IPv4 form, and the
+// IPv6 prefixes shorter than /96, such as ::/0, that contain the mapped range.
+type PrefixTable[T any] struct {
+	v4 bitTrie[T]
+	v6 bitTrie[T]
+}
+
+// v4Mapped is the range of IPv4-mapped IPv6 addresses.
+var v4Mapped = netip.MustParsePrefix("::ffff:0.0.0.0/96")
+
+// NewPrefixTable creates a new IP prefix table.
+func NewPrefixTable[T any]() *PrefixTable[T] {
+	return new(PrefixTable[T])
+}
+
+// Len returns the number of prefixes stored in the table.
+func (pt *PrefixTable[T]) Len() int {
+	return pt.v4.size + pt.v6.size
+}
+
+// Insert stores the value at the given prefix, replacing any existing value.
+// Bits of the prefix address beyond the prefix length are ignored. It returns
+// true if it adds a new prefix, false if it replaces an existing value or the
+// prefix is not valid.
+func (pt *PrefixTable[T]) Insert(prefix netip.Prefix, value T) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	prefix = unmapPrefix(prefix)
+	trie, key := pt.trie(prefix.Addr())
+	return trie.put(key[:], prefix.Bits(), value)
+}
+
+// Get returns the value stored at exactly the given prefix. Returns false if
+// there is no value present for the prefix.
+func (pt *PrefixTable[T]) Get(prefix netip.Prefix) (T, bool) {
+	var zero T
+	if !prefix.IsValid() {
+		return zero, false
+	}
+	prefix = unmapPrefix(prefix)
+	trie, key := pt.trie(prefix.Addr())
+	node := trie.get(key[:], prefix.Bits())
+	if node == nil {
+		return zero, false
+	}
+	return node.value, true
+}
+
+// Delete removes the value stored at exactly the given prefix. Returns true if
+// there was a value stored for the prefix.
+func (pt *PrefixTable[T]) Delete(prefix netip.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	prefix = unmapPrefix(prefix)
+	trie, key := pt.trie(prefix.Addr())
+	return trie.delete(key[:], prefix.Bits())
+}
+
+// Lookup returns the longest prefix that contains the address, and its value.
+// Returns false if no prefix contains the address.
+func (pt *PrefixTable[T]) Lookup(addr netip.Addr) (netip.Prefix, T, bool) {
+	var zero T
+	if !addr.IsValid() {
+		return netip.Prefix{}, zero, false
+	}
+	unmapped := addr.Unmap()
+	trie, key := pt.trie(unmapped)
+	if node := trie.longest(key[:], unmapped.BitLen()); node != nil {
+		return pt.prefix(unmapped.Is4(), node), node.value, true
+	}
+	if addr.Is4In6() {
+		// No IPv4 prefix contains the address, so find the longest IPv6
+		// prefix that contains the mapped range.
+		a16 := addr.As16()
+		if node := pt.v6.longest(a16[:], v4Mapped.Bits()); node != nil {
+			return pt.prefix(false, node), node.value, true
+		}
+	}
+	return netip.Prefix{}, zero, false
+}
+
+// Contains returns true if any prefix in the table contains the address.
+func (pt *PrefixTable[T]) Contains(addr netip.Addr) bool {
+	_, _, ok := pt.Lookup(addr)
+	return ok
+}
+
+// Supernets visits each stored prefix that contains the given prefix,
+// including the prefix itself, yielding the prefix and value of each. Prefixes
+// are visited from shortest to longest, so for an IPv4-mapped prefix, the
+// IPv6 prefixes that contain the mapped range are visited first.
+func (pt *PrefixTable[T]) Supernets(prefix netip.Prefix) iter.Seq2[netip.Prefix, T] {
+	return func(yield func(netip.Prefix, T) bool) {
+		if !prefix.IsValid() {
+			return
+		}
+		mapped := unmapPrefix(prefix)
+		is4 := mapped.Addr().Is4()
+		if is4 && prefix.Addr().Is4In6() {
+			a16 := prefix.Addr().As16()
+			if !pt.v6.path(a16[:], v4Mapped.Bits(), pt.yielder(false, yield)) {
+				return
+			}
+		}
+		trie, key := pt.trie(mapped.Addr())
+		trie.path(key[:], mapped.Bits(), pt.yielder(is4, yield))
+	}
+}
+
+// Subnets visits each stored prefix that is contained by the given prefix,
+// including the prefix itself, yielding the prefix and value of each. Prefixes
+// are visited in order of address, with shorter prefixes first. An IPv6
+// prefix that contains the mapped range, such as ::/0, also contains all IPv4
+// prefixes, which are visited in the position of the mapped range.
+func (pt *PrefixTable[T]) Subnets(prefix netip.Prefix) iter.Seq2[netip.Prefix, T] {
+	return func(yield func(netip.Prefix, T) bool) {
+		if !prefix.IsValid() {
+			return
+		}
+		prefix = unmapPrefix(prefix)
+		is4 := prefix.Addr().Is4()
+		trie, key := pt.trie(prefix.Addr())
+		yieldNode := pt.yielder(is4, yield)
+		withV4 := !is4 && prefix.Bits() <= v4Mapped.Bits() && prefix.Contains(v4Mapped.Addr())
+		if !withV4 {
+			trie.prefixed(key[:], prefix.Bits()).walk(yieldNode)
+			return
+		}
+		yieldV4 := pt.yielder(true, yield)
+		m16 := v4Mapped.Addr().As16()
+		ok := trie.prefixed(key[:], prefix.Bits()).walk(func(node *bitNode[T]) bool {
+			// Visit the IPv4 prefixes before the first IPv6 prefix that
+			// neither contains the mapped range nor comes before it.
+			if withV4 && commonBits(node.key, node.n, m16[:], v4Mapped.Bits()) != node.n &&
+				node.key > string(m16[:len(node.key)]) {
+				withV4 = false
+				if !pt.v4.root.walk(yieldV4) {
+					return false
+				}
+			}
+			return yieldNode(node)
+		})
+		if ok && withV4 {
+			pt.v4.root.walk(yieldV4)
+		}
+	}
+}
+
+// All visits every prefix in the table, yielding the prefix and value of each.
+// IPv4 prefixes are visited before IPv6 prefixes, each in order of address,
+// with shorter prefixes first.
+func (pt *PrefixTable[T]) All() iter.Seq2[netip.Prefix, T] {
+	return func(yield func(netip.Prefix, T) bool) {
+		if pt.v4.root.walk(pt.yielder(true, yield)) {
+			pt.v6.root.walk(pt.yielder(false, yield))
+		}
+	}
+}
+
+// yielder returns a function that yields the prefix and value of a node from
+// the trie for the address family.
+func (pt *PrefixTable[T]) yielder(is4 bool, yield func(netip.Prefix, T) bool) func(*bitNode[T]) bool {
+	return func(node *bitNode[T]) bool {
+		return yield(pt.prefix(is4, node), node.value)
+	}
+}
+
+// trie returns the trie for the address family, and the address bytes used as
+// a key. An IPv4 key uses only the first 4 bytes.
+func (pt *PrefixTable[T]) trie(addr netip.Addr) (*bitTrie[T], [16]byte) {
+	var key [16]byte
+	if addr.Is4() {
+		a4 := addr.As4()
+		copy(key[:], a4[:])
+		return &pt.v4, key
+	}
+	return &pt.v6, addr.As16()
+}
+
+// unmapPrefix converts an IPv4-mapped IPv6 prefix that covers only mapped
+// addresses into the equivalent IPv4 prefix.
+func unmapPrefix(prefix netip.Prefix) netip.Prefix {
+	addr := prefix.Addr()
+	if !addr.Is4In6() || prefix.Bits() < 96 {
+		return prefix
+	}
+	return netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+}
+
+// prefix converts the key of a node back into a network prefix.
+func (pt *PrefixTable[T]) prefix(is4 bool, node *bitNode[T]) netip.Prefix {
+	if is4 {
+		var a4 [4]byte
+		copy(a4[:], node.key)
+		return netip.PrefixFrom(netip.AddrFrom4(a4), node.n)
+	}
+	var a16 [16]byte
+	copy(a16[:], node.key)
+	return netip.PrefixFrom(netip.AddrFrom16(a16), node.n)
+}
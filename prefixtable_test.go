@@ -0,0 +1,241 @@
+package radixtree
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestPrefixTable(t *testing.T) {
+	pt := NewPrefixTable[string]()
+	prefixes := []string{
+		"0.0.0.0/0",
+		"10.0.0.0/8",
+		"10.8.0.0/13",
+		"10.8.1.0/24",
+		"10.8.1.32/27",
+		"10.16.0.0/13",
+		"192.168.0.0/16",
+		"2001:db8::/32",
+		"2001:db8:8000::/33",
+		"::/0",
+	}
+	for _, s := range prefixes {
+		if !pt.Insert(netip.MustParsePrefix(s), s) {
+			t.Fatalf("expected new prefix %s", s)
+		}
+	}
+	if pt.Len() != len(prefixes) {
+		t.Fatalf("expected %d prefixes, got %d", len(prefixes), pt.Len())
+	}
+	if pt.Insert(netip.MustParsePrefix("10.8.1.55/24"), "10.8.1.0/24") {
+		t.Fatal("expected existing prefix to be replaced")
+	}
+	if pt.Insert(netip.Prefix{}, "") {
+		t.Fatal("should not insert invalid prefix")
+	}
+
+	lookups := map[string]string{
+		"10.8.1.33":        "10.8.1.32/27",
+		"10.8.1.64":        "10.8.1.0/24",
+		"10.15.255.255":    "10.8.0.0/13",
+		"10.16.0.1":        "10.16.0.0/13",
+		"10.24.0.1":        "10.0.0.0/8",
+		"11.0.0.1":         "0.0.0.0/0",
+		"::ffff:10.8.1.33": "10.8.1.32/27",
+		"2001:db8::1":      "2001:db8::/32",
+		"2001:db8:8001::1": "2001:db8:8000::/33",
+		"2001:db9::1":      "::/0",
+	}
+	for addr, expect := range lookups {
+		p, val, ok := pt.Lookup(netip.MustParseAddr(addr))
+		if !ok || val != expect || p.String() != expect {
+			t.Errorf("lookup %s: expected %s, got %s %s", addr, expect, p, val)
+		}
+	}
+
+	if val, ok := pt.Get(netip.MustParsePrefix("10.8.0.0/13")); !ok || val != "10.8.0.0/13" {
+		t.Fatal("expected value at 10.8.0.0/13")
+	}
+	if _, ok := pt.Get(netip.MustParsePrefix("10.8.0.0/14")); ok {
+		t.Fatal("expected no value at 10.8.0.0/14")
+	}
+
+	var found []string
+	for p, val := range pt.Supernets(netip.MustParsePrefix("10.8.1.40/29")) {
+		if p.String() != val {
+			t.Fatalf("wrong value %s for prefix %s", val, p)
+		}
+		found = append(found, val)
+	}
+	expect := []string{"0.0.0.0/0", "10.0.0.0/8", "10.8.0.0/13", "10.8.1.0/24", "10.8.1.32/27"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected supernets %v, got %v", expect, found)
+	}
+
+	found = found[:0]
+	for p := range pt.Subnets(netip.MustParsePrefix("10.0.0.0/8")) {
+		found = append(found, p.String())
+	}
+	expect = []string{"10.0.0.0/8", "10.8.0.0/13", "10.8.1.0/24", "10.8.1.32/27", "10.16.0.0/13"}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected subnets %v, got %v", expect, found)
+	}
+	found = found[:0]
+	for p := range pt.Subnets(netip.MustParsePrefix("10.12.0.0/14")) {
+		found = append(found, p.String())
+	}
+	if len(found) != 0 {
+		t.Fatal("expected no subnets, got", found)
+	}
+
+	found = found[:0]
+	for p := range pt.All() {
+		found = append(found, p.String())
+	}
+	expect = []string{
+		"0.0.0.0/0", "10.0.0.0/8", "10.8.0.0/13", "10.8.1.0/24", "10.8.1.32/27",
+		"10.16.0.0/13", "192.168.0.0/16", "::/0", "2001:db8::/32", "2001:db8:8000::/33",
+	}
+	if !slices.Equal(found, expect) {
+		t.Fatalf("expected all %v, got %v", expect, found)
+	}
+
+	// Delete and check that lookups fall back to shorter prefixes.
+	if !pt.Delete(netip.MustParsePrefix("10.8.1.32/27")) {
+		t.Fatal("expected prefix to be deleted")
+	}
+	if pt.Delete(netip.MustParsePrefix("10.8.1.32/27")) {
+		t.Fatal("prefix should already be deleted")
+	}
+	if pt.Delete(netip.MustParsePrefix("10.8.1.0/25")) {
+		t.Fatal("should not delete prefix that is not stored")
+	}
+	if _, val, _ := pt.Lookup(netip.MustParseAddr("10.8.1.33")); val != "10.8.1.0/24" {
+		t.Fatal("expected lookup to fall back to 10.8.1.0/24, got", val)
+	}
+	for _, s := range []string{"0.0.0.0/0", "10.8.0.0/13", "10.16.0.0/13"} {
+		if !pt.Delete(netip.MustParsePrefix(s)) {
+			t.Fatal("expected prefix to be deleted:", s)
+		}
+	}
+	if pt.Contains(netip.MustParseAddr("11.0.0.1")) {
+		t.Fatal("should not contain 11.0.0.1")
+	}
+	if !pt.Contains(netip.MustParseAddr("10.8.1.1")) {
+		t.Fatal("should contain 10.8.1.1")
+	}
+	if pt.Len() != len(prefixes)-4 {
+		t.Fatalf("expected %d prefixes, got %d", len(prefixes)-4, pt.Len())
+	}
+	if pt.Contains(netip.Addr{}) {
+		t.Fatal("should not contain invalid address")
+	}
+}
+
+func TestPrefixTableMapped(t *testing.T) {
+	pt := NewPrefixTable[int]()
+	if !pt.Insert(netip.MustParsePrefix("::ffff:10.0.0.0/104"), 1) {
+		t.Fatal("expected mapped prefix to be inserted")
+	}
+	if pt.Insert(netip.MustParsePrefix("10.0.0.0/8"), 2) {
+		t.Fatal("expected equivalent IPv4 prefix to replace mapped prefix")
+	}
+	if pt.Len() != 1 {
+		t.Fatalf("expected 1 prefix, got %d", pt.Len())
+	}
+
+	for _, addr := range []string{"10.0.0.1", "::ffff:10.0.0.1"} {
+		p, val, ok := pt.Lookup(netip.MustParseAddr(addr))
+		if !ok || val != 2 || p != netip.MustParsePrefix("10.0.0.0/8") {
+			t.Fatalf("lookup %s: got %s %d %t", addr, p, val, ok)
+		}
+	}
+	if val, ok := pt.Get(netip.MustParsePrefix("::ffff:10.0.0.0/104")); !ok || val != 2 {
+		t.Fatal("expected Get of mapped prefix to find IPv4 prefix")
+	}
+
+	var n int
+	for range pt.Supernets(netip.MustParsePrefix("::ffff:10.1.0.0/112")) {
+		n++
+	}
+	for range pt.Subnets(netip.MustParsePrefix("::ffff:0.0.0.0/96")) {
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("expected mapped supernet and subnet queries to find the prefix, got %d", n)
+	}
+
+	// A prefix shorter than the mapped range remains IPv6.
+	pt.Insert(netip.MustParsePrefix("::ffff:0:0/80"), 3)
+	if _, val, _ := pt.Lookup(netip.MustParseAddr("10.0.0.1")); val != 2 {
+		t.Fatal("expected IPv4 lookup to be unaffected by IPv6 prefix")
+	}
+
+	if !pt.Delete(netip.MustParsePrefix("::ffff:10.0.0.0/104")) || pt.Len() != 1 {
+		t.Fatal("expected mapped prefix to be deleted")
+	}
+}
+
+func TestPrefixTableMappedInIPv6(t *testing.T) {
+	pt := NewPrefixTable[string]()
+	for _, s := range []string{"::/0", "::ffff:0:0/95", "10.0.0.0/8", "2001:db8::/32", "::1/128"} {
+		pt.Insert(netip.MustParsePrefix(s), s)
+	}
+
+	lookups := map[string]string{
+		"::ffff:1.2.3.4":  "::ffff:0:0/95",
+		"::ffff:10.0.0.1": "10.0.0.0/8",
+		"1.2.3.4":         "",
+	}
+	for addr, expect := range lookups {
+		p, val, ok := pt.Lookup(netip.MustParseAddr(addr))
+		if expect == "" {
+			if ok {
+				t.Errorf("lookup %s: expected no match, got %s", addr, p)
+			}
+			continue
+		}
+		if !ok || val != expect {
+			t.Errorf("lookup %s: expected %s, got %s", addr, expect, val)
+		}
+	}
+	if !pt.Contains(netip.MustParseAddr("::ffff:1.2.3.4")) {
+		t.Fatal("expected ::/0 to contain mapped address")
+	}
+
+	var found []string
+	for _, val := range pt.Supernets(netip.MustParsePrefix("::ffff:10.1.0.0/112")) {
+		found = append(found, val)
+	}
+	want := []string{"::/0", "::ffff:0:0/95", "10.0.0.0/8"}
+	if !slices.Equal(found, want) {
+		t.Fatalf("expected supernets %q, got %q", want, found)
+	}
+
+	found = nil
+	for _, val := range pt.Subnets(netip.MustParsePrefix("::/0")) {
+		found = append(found, val)
+	}
+	want = []string{"::/0", "::1/128", "::ffff:0:0/95", "10.0.0.0/8", "2001:db8::/32"}
+	if !slices.Equal(found, want) {
+		t.Fatalf("expected subnets %q, got %q", want, found)
+	}
+
+	found = nil
+	for _, val := range pt.Subnets(netip.MustParsePrefix("2000::/3")) {
+		found = append(found, val)
+	}
+	if !slices.Equal(found, []string{"2001:db8::/32"}) {
+		t.Fatalf("expected only 2001:db8::/32, got %q", found)
+	}
+
+	var n int
+	for range pt.Subnets(netip.MustParsePrefix("::/0")) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatal("iteration did not stop")
+	}
+}
@@ -0,0 +1,386 @@
+package radixtree
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxRegexpStates limits the number of DFA states built for a regular
+// expression.
+const maxRegexpStates = 10000
+
+// ErrRegexpTooComplex is returned by CompileRegexp when the regular expression
+// requires too many DFA states.
+var ErrRegexpTooComplex = errors.New("radixtree: regular expression too complex")
+
+// RegexpAutomaton is an Automaton that accepts keys matching a regular
+// expression. The whole key must match, as if the expression were anchored at
+// both ends.
+//
+// Use CompileRegexp to create a RegexpAutomaton. It is immutable, and may be
+// used by any number of iterations concurrently.
+type RegexpAutomaton struct {
+	// trans holds the 256 transitions for each state, indexed by state<<8|b.
+	trans []int32
+	match []bool
+	live  []bool
+}
+
+const (
+	regexpDead  = 0
+	regexpStart = 1
+)
+
+// CompileRegexp compiles a regular expression, in the syntax accepted by the
+// regexp package, into a deterministic automaton.
+//
+// Keys are matched one byte at a time. Literals, character classes and the '.'
+// operator match the UTF-8 encoding of their runes, including the runes added
+// by case folding, such as the Kelvin sign matched by (?i)[a-z]. As in RE2,
+// bytes that are not part of a valid UTF-8 encoding are not matched by '.' or
+// by any character class. Multi-line anchors and word boundaries are not
+// supported, and cause an error to be returned.
+func CompileRegexp(expr string) (*RegexpAutomaton, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkRegexp(re); err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	return buildRegexpAutomaton(prog)
+}
+
+// Start returns the initial state.
+func (r *RegexpAutomaton) Start() int { return regexpStart }
+
+// Step returns the state reached by consuming b in the given state.
+func (r *RegexpAutomaton) Step(state int, b byte) int {
+	return int(r.trans[state<<8|int(b)])
+}
+
+// IsMatch reports whether the key consumed to reach the state matches.
+func (r *RegexpAutomaton) IsMatch(state int) bool { return r.match[state] }
+
+// CanMatch reports whether any match is reachable from the state.
+func (r *RegexpAutomaton) CanMatch(state int) bool { return r.live[state] }
+
+// checkRegexp returns an error if the regular expression uses features that
+// cannot be matched one byte at a time. Literals, character classes and the
+// '.' operator that match non-ASCII runes are rewritten to match the bytes of
+// their UTF-8 encodings.
+func checkRegexp(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpLiteral:
+		fold := re.Flags&syntax.FoldCase != 0
+		for _, r := range re.Rune {
+			if class := literalClass(r, fold); class[len(class)-1] >= utf8.RuneSelf {
+				*re = *utf8Literal(re)
+				break
+			}
+		}
+		return nil
+	case syntax.OpCharClass:
+		*re = *utf8Class(re.Rune)
+		return nil
+	case syntax.OpAnyChar:
+		*re = *utf8Class([]rune{0, unicode.MaxRune})
+		return nil
+	case syntax.OpAnyCharNotNL:
+		*re = *utf8Class([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune})
+		return nil
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return fmt.Errorf("radixtree: unsupported %s in regular expression", re)
+	}
+	for _, sub := range re.Sub {
+		if err := checkRegexp(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// literalClass returns the character class ranges matched by the literal rune.
+// With case folding, the class holds each case variant of the rune.
+func literalClass(r rune, fold bool) []rune {
+	class := []rune{r, r}
+	if !fold {
+		return class
+	}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		class = append(class, f, f)
+	}
+	slices.Sort(class)
+	return class
+}
+
+// utf8Literal returns a regular expression that matches the literal one byte
+// at a time. Runes that are ASCII, along with all of their case variants, are
+// kept as literals, and others are matched as a class of their variants.
+func utf8Literal(re *syntax.Regexp) *syntax.Regexp {
+	cat := &syntax.Regexp{Op: syntax.OpConcat}
+	for _, r := range re.Rune {
+		class := literalClass(r, re.Flags&syntax.FoldCase != 0)
+		if class[len(class)-1] < utf8.RuneSelf {
+			cat.Sub = append(cat.Sub, &syntax.Regexp{
+				Op:    syntax.OpLiteral,
+				Rune:  []rune{r},
+				Flags: re.Flags,
+			})
+			continue
+		}
+		cat.Sub = append(cat.Sub, utf8Class(class))
+	}
+	return cat
+}
+
+// utf8Class returns a regular expression that matches the UTF-8 encoding of
+// any rune in the character class ranges, as an alternation of sequences of
+// byte classes.
+func utf8Class(ranges []rune) *syntax.Regexp {
+	alt := &syntax.Regexp{Op: syntax.OpAlternate}
+	bytes := &syntax.Regexp{Op: syntax.OpCharClass}
+	var seqs [][]byteRange
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < utf8.RuneSelf {
+			bytes.Rune = append(bytes.Rune, lo, min(hi, utf8.RuneSelf-1))
+			lo = utf8.RuneSelf
+		}
+		if lo > hi {
+			continue
+		}
+		seqs = utf8Sequences(seqs, lo, hi)
+	}
+	if len(bytes.Rune) != 0 {
+		alt.Sub = append(alt.Sub, bytes)
+	}
+	for _, seq := range seqs {
+		cat := &syntax.Regexp{Op: syntax.OpConcat}
+		for _, br := range seq {
+			cat.Sub = append(cat.Sub, &syntax.Regexp{
+				Op:   syntax.OpCharClass,
+				Rune: []rune{rune(br.lo), rune(br.hi)},
+			})
+		}
+		alt.Sub = append(alt.Sub, cat)
+	}
+	switch len(alt.Sub) {
+	case 0:
+		return &syntax.Regexp{Op: syntax.OpNoMatch}
+	case 1:
+		return alt.Sub[0]
+	}
+	return alt
+}
+
+// byteRange is a range of byte values, from lo to hi inclusive.
+type byteRange struct {
+	lo, hi byte
+}
+
+// utf8Sequences appends to seqs the sequences of byte ranges that together
+// match the UTF-8 encodings of the runes from lo to hi. Surrogates, which have
+// no UTF-8 encoding, are skipped. The range is split until the runes in each
+// part have encodings of the same length that differ only in bytes that range
+// over all continuation bytes, as in RE2.
+func utf8Sequences(seqs [][]byteRange, lo, hi rune) [][]byteRange {
+	if lo <= 0xdfff && hi >= 0xd800 {
+		if lo < 0xd800 {
+			seqs = utf8Sequences(seqs, lo, 0xd7ff)
+		}
+		if hi > 0xdfff {
+			seqs = utf8Sequences(seqs, 0xe000, hi)
+		}
+		return seqs
+	}
+	for _, max := range []rune{0x7f, 0x7ff, 0xffff} {
+		if lo <= max && hi > max {
+			seqs = utf8Sequences(seqs, lo, max)
+			return utf8Sequences(seqs, max+1, hi)
+		}
+	}
+	if hi < utf8.RuneSelf {
+		return append(seqs, []byteRange{{byte(lo), byte(hi)}})
+	}
+	n := utf8.RuneLen(lo)
+	for i := 1; i < n; i++ {
+		m := rune(1)<<(6*i) - 1
+		if lo&^m == hi&^m {
+			continue
+		}
+		if lo&m != 0 {
+			seqs = utf8Sequences(seqs, lo, lo|m)
+			return utf8Sequences(seqs, (lo|m)+1, hi)
+		}
+		if hi&m != m {
+			seqs = utf8Sequences(seqs, lo, (hi&^m)-1)
+			return utf8Sequences(seqs, hi&^m, hi)
+		}
+	}
+	var a, b [utf8.UTFMax]byte
+	utf8.EncodeRune(a[:], lo)
+	utf8.EncodeRune(b[:], hi)
+	seq := make([]byteRange, n)
+	for i := range seq {
+		seq[i] = byteRange{a[i], b[i]}
+	}
+	return append(seqs, seq)
+}
+
+// regexpBuilder converts a compiled regexp program into a DFA by subset
+// construction. Each DFA state is the set of program instructions that are
+// waiting to consume a byte, or waiting for the end of the key.
+type regexpBuilder struct {
+	prog  *syntax.Prog
+	sets  [][]uint32
+	index map[string]int
+	seen  []bool
+	key   []byte
+}
+
+func buildRegexpAutomaton(prog *syntax.Prog) (*RegexpAutomaton, error) {
+	b := &regexpBuilder{
+		prog:  prog,
+		index: map[string]int{},
+		seen:  make([]bool, len(prog.Inst)),
+	}
+	// The dead state is the empty set. The start state is never shared with
+	// another state, since the beginning of the key is only matched there.
+	b.sets = append(b.sets, nil)
+	b.index[""] = regexpDead
+	b.sets = append(b.sets, b.closure(nil, uint32(prog.Start), syntax.EmptyBeginText|syntax.EmptyBeginLine))
+
+	r := &RegexpAutomaton{}
+	for s := 0; s < len(b.sets); s++ {
+		if len(b.sets) > maxRegexpStates {
+			return nil, ErrRegexpTooComplex
+		}
+		var flags syntax.EmptyOp
+		if s == regexpStart {
+			flags = syntax.EmptyBeginText | syntax.EmptyBeginLine
+		}
+		r.match = append(r.match, b.isMatch(b.sets[s], flags))
+		for c := 0; c < 256; c++ {
+			r.trans = append(r.trans, int32(b.step(b.sets[s], byte(c))))
+		}
+	}
+
+	// A state is live if it matches or has a transition to a live state.
+	r.live = slices.Clone(r.match)
+	for changed := true; changed; {
+		changed = false
+		for s := len(r.live) - 1; s >= 0; s-- {
+			if r.live[s] {
+				continue
+			}
+			for _, next := range r.trans[s<<8 : (s+1)<<8] {
+				if r.live[next] {
+					r.live[s] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// step returns the DFA state reached from the set of instructions by
+// consuming c, adding a new state if needed.
+func (b *regexpBuilder) step(set []uint32, c byte) int {
+	var next []uint32
+	for _, pc := range set {
+		inst := &b.prog.Inst[pc]
+		var ok bool
+		switch inst.Op {
+		case syntax.InstRune1:
+			ok = rune(c) == inst.Rune[0]
+		case syntax.InstRune:
+			ok = inst.MatchRune(rune(c))
+		}
+		if ok {
+			next = b.closure(next, inst.Out, 0)
+		}
+	}
+	slices.Sort(next)
+	next = slices.Compact(next)
+
+	b.key = b.key[:0]
+	for _, pc := range next {
+		b.key = append(b.key, byte(pc), byte(pc>>8), byte(pc>>16), byte(pc>>24))
+	}
+	if s, ok := b.index[string(b.key)]; ok {
+		return s
+	}
+	s := len(b.sets)
+	b.index[string(b.key)] = s
+	b.sets = append(b.sets, next)
+	return s
+}
+
+// closure adds pc to the set, following all instructions that do not consume
+// a byte. Empty-width assertions are followed if satisfied by flags, and are
+// kept in the set if they may be satisfied by the end of the key.
+func (b *regexpBuilder) closure(set []uint32, pc uint32, flags syntax.EmptyOp) []uint32 {
+	stack := []uint32{pc}
+	var visited []uint32
+	for len(stack) != 0 {
+		pc = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if b.seen[pc] {
+			continue
+		}
+		b.seen[pc] = true
+		visited = append(visited, pc)
+
+		inst := &b.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			stack = append(stack, inst.Arg, inst.Out)
+		case syntax.InstNop, syntax.InstCapture:
+			stack = append(stack, inst.Out)
+		case syntax.InstEmptyWidth:
+			need := syntax.EmptyOp(inst.Arg)
+			if need&^flags == 0 {
+				stack = append(stack, inst.Out)
+			} else if need&^(flags|syntax.EmptyEndText|syntax.EmptyEndLine) == 0 {
+				set = append(set, pc)
+			}
+		case syntax.InstFail:
+		default:
+			set = append(set, pc)
+		}
+	}
+	for _, pc = range visited {
+		b.seen[pc] = false
+	}
+	return set
+}
+
+// isMatch reports whether the set of instructions matches at the end of the
+// key.
+func (b *regexpBuilder) isMatch(set []uint32, flags syntax.EmptyOp) bool {
+	flags |= syntax.EmptyEndText | syntax.EmptyEndLine
+	for _, pc := range set {
+		switch b.prog.Inst[pc].Op {
+		case syntax.InstMatch:
+			return true
+		case syntax.InstEmptyWidth:
+			for _, end := range b.closure(nil, pc, flags) {
+				if b.prog.Inst[end].Op == syntax.InstMatch {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
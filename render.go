@@ -0,0 +1,198 @@
+package radixtree
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RenderOptions limits how much of a tree is written by WriteDOT and
+// WriteText. Parts of the tree beyond the limits are shown as "...".
+type RenderOptions struct {
+	// MaxDepth is the greatest number of edges between the root and a node
+	// that is written. Zero means no limit.
+	MaxDepth int
+	// MaxNodes is the greatest number of nodes that are written, including the
+	// root. Zero means no limit.
+	MaxNodes int
+}
+
+// WriteDOT writes the structure of the tree to w as a Graphviz DOT graph. Each
+// edge is labeled with its radix byte followed by the prefix of the node that
+// it leads to, and each node that holds a value is labeled with the value.
+func (t *Tree[T]) WriteDOT(w io.Writer, opts RenderOptions) error {
+	r := renderer[T]{
+		w:    w,
+		opts: opts,
+	}
+	r.printf("digraph radixtree {\n")
+	r.printf("\tnode [shape=box];\n")
+	r.printf("\tn0 [label=%s];\n", dotQuote(r.nodeLabel(&t.root, "root")...))
+	r.nodes = 1
+	r.dotChildren(&t.root, 0, 0)
+	r.printf("}\n")
+	return r.err
+}
+
+// WriteText writes the structure of the tree to w as text, with each node
+// shown as its prefix and value, and each edge as its radix byte. Values that
+// are not printable text are quoted. The first child of a node follows it on
+// the same line, and the other children are indented below the first:
+//
+//	(root) t-> ("o", _) m-> ("", 1) a-> ("to", 2)
+//	                    r-> ("n", 3)
+func (t *Tree[T]) WriteText(w io.Writer, opts RenderOptions) error {
+	r := renderer[T]{
+		w:     w,
+		opts:  opts,
+		nodes: 1,
+	}
+	label := "(root)"
+	if t.root.leaf != nil {
+		label = fmt.Sprintf("(root, %s)", textValue(t.root.leaf.value))
+	}
+	r.textNode(&t.root, label, 0, 0)
+	return r.err
+}
+
+type renderer[T any] struct {
+	w     io.Writer
+	opts  RenderOptions
+	nodes int
+	err   error
+}
+
+func (r *renderer[T]) printf(format string, args ...any) {
+	if r.err == nil {
+		_, r.err = fmt.Fprintf(r.w, format, args...)
+	}
+}
+
+// truncated returns true if the children of a node at the given depth are
+// not to be written.
+func (r *renderer[T]) truncated(depth int) bool {
+	return (r.opts.MaxDepth > 0 && depth >= r.opts.MaxDepth) ||
+		(r.opts.MaxNodes > 0 && r.nodes >= r.opts.MaxNodes)
+}
+
+// nodeLabel returns the lines of a node's label, which are the given name, if
+// not empty, followed by the value of the node as text, if it holds a value.
+func (r *renderer[T]) nodeLabel(node *radixNode[T], name string) []string {
+	var lines []string
+	if name != "" {
+		lines = append(lines, name)
+	}
+	if node.leaf != nil {
+		lines = append(lines, fmt.Sprint(node.leaf.value))
+	}
+	return lines
+}
+
+// dotChildren writes the children of the node with the given id and depth,
+// and the edges to them.
+func (r *renderer[T]) dotChildren(node *radixNode[T], id, depth int) {
+	for i, child := range node.nodes {
+		if r.truncated(depth) {
+			r.printf("\tn%d_more [label=\"...\", shape=plaintext];\n", id)
+			r.printf("\tn%d -> n%d_more;\n", id, id)
+			return
+		}
+		childID := r.nodes
+		r.nodes++
+		if child.leaf != nil {
+			r.printf("\tn%d [label=%s];\n", childID, dotQuote(r.nodeLabel(child, "")...))
+		} else {
+			r.printf("\tn%d [label=\"\", shape=point];\n", childID)
+		}
+		edge := string(node.radices[i:i+1]) + child.prefix
+		r.printf("\tn%d -> n%d [label=%s];\n", id, childID, dotQuote(edge))
+		r.dotChildren(child, childID, depth+1)
+	}
+}
+
+// textNode writes the node, whose label is the given text, starting at the
+// given column, followed by its children.
+func (r *renderer[T]) textNode(node *radixNode[T], label string, depth, col int) {
+	r.printf("%s", label)
+	col += utf8.RuneCountInString(label) + 1
+	if len(node.nodes) == 0 {
+		r.printf("\n")
+		return
+	}
+	for i, child := range node.nodes {
+		if i == 0 {
+			r.printf(" ")
+		} else {
+			r.printf("%s", strings.Repeat(" ", col))
+		}
+		if r.truncated(depth) {
+			r.printf("...\n")
+			return
+		}
+		r.nodes++
+		link := textRadix(node.radices[i]) + "-> "
+		r.printf("%s", link)
+		value := "_"
+		if child.leaf != nil {
+			value = textValue(child.leaf.value)
+		}
+		childLabel := fmt.Sprintf("(%q, %s)", child.prefix, value)
+		r.textNode(child, childLabel, depth+1, col+utf8.RuneCountInString(link))
+	}
+}
+
+// textRadix returns the radix byte as text, quoted if it is not printable.
+// Bytes are quoted as a one-byte string, so that a non-ASCII byte is shown as
+// an \x escape, as it is in a prefix.
+func textRadix(radix byte) string {
+	if radix > ' ' && radix < 0x7f {
+		return string(radix)
+	}
+	return strconv.Quote(string([]byte{radix}))
+}
+
+// textValue returns the value as text. It is quoted if it contains characters
+// that are not printable or bytes that are not valid UTF-8, so that it stays
+// on one line.
+func textValue[T any](value T) string {
+	s := fmt.Sprint(value)
+	if !utf8.ValidString(s) || strings.ContainsFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// dotQuote returns the lines as a quoted DOT string, separated by DOT line
+// breaks. Quotes and backslashes are escaped. Control characters and invalid
+// UTF-8 bytes are written as Go escape sequences, as in WriteText, with the
+// backslash escaped so that the sequence is shown in the label.
+func dotQuote(lines ...string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, line := range lines {
+		if i != 0 {
+			b.WriteString(`\n`)
+		}
+		for j := 0; j < len(line); {
+			r, size := utf8.DecodeRuneInString(line[j:])
+			switch {
+			case r == utf8.RuneError && size == 1:
+				fmt.Fprintf(&b, `\\x%02x`, line[j])
+			case r == '"', r == '\\':
+				b.WriteByte('\\')
+				b.WriteRune(r)
+			case !unicode.IsPrint(r):
+				q := strconv.QuoteRune(r)
+				b.WriteString(strings.ReplaceAll(q[1:len(q)-1], `\`, `\\`))
+			default:
+				b.WriteRune(r)
+			}
+			j += size
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
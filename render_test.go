@@ -0,0 +1,139 @@
+package radixtree
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestWriteText(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("torn", "TORN")
+
+	var b strings.Builder
+	if err := rt.WriteText(&b, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `(root) t-> ("o", _) m-> ("", TOM) a-> ("to", TOMATO)
+                    r-> ("n", TORN)
+`
+	if b.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+
+	rt.Put("", "EMPTY")
+	rt.Put("tag", "TAG")
+	b.Reset()
+	rt.WriteText(&b, RenderOptions{MaxDepth: 2})
+	want = `(root, EMPTY) t-> ("", _) a-> ("g", TAG)
+                          o-> ("", _) ...
+`
+	if b.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+
+	b.Reset()
+	rt.WriteText(&b, RenderOptions{MaxNodes: 3})
+	want = `(root, EMPTY) t-> ("", _) a-> ("g", TAG)
+                          ...
+`
+	if b.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+
+	b.Reset()
+	New[int]().WriteText(&b, RenderOptions{})
+	if b.String() != "(root)\n" {
+		t.Fatalf("unexpected rendering of empty tree: %q", b.String())
+	}
+}
+
+func TestWriteTextBinary(t *testing.T) {
+	rt := New[string]()
+	rt.Put("\xc3a", "tab\tnl\nend")
+	rt.Put("\xc3b", "B")
+	rt.Put(" c", "\xff")
+
+	var b strings.Builder
+	if err := rt.WriteText(&b, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `(root) " "-> ("c", "\xff")
+       "\xc3"-> ("", _) a-> ("", "tab\tnl\nend")
+                        b-> ("", B)
+`
+	if b.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", `"quoted"`)
+	rt.Put("torn", "TORN")
+
+	var b strings.Builder
+	if err := rt.WriteDOT(&b, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph radixtree {
+	node [shape=box];
+	n0 [label="root"];
+	n1 [label="", shape=point];
+	n0 -> n1 [label="to"];
+	n2 [label="TOM"];
+	n1 -> n2 [label="m"];
+	n3 [label="\"quoted\""];
+	n2 -> n3 [label="ato"];
+	n4 [label="TORN"];
+	n1 -> n4 [label="rn"];
+}
+`
+	if b.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+
+	b.Reset()
+	rt.WriteDOT(&b, RenderOptions{MaxNodes: 2})
+	if !strings.Contains(b.String(), "n1 -> n1_more;") || strings.Contains(b.String(), "n2 ") {
+		t.Fatalf("expected truncated graph, got:\n%s", b.String())
+	}
+}
+
+func TestWriteDOTBinaryKeys(t *testing.T) {
+	rt := New[string]()
+	rt.Put("a\x00b", "nul")
+	rt.Put("a\xffc", "line\nbreak")
+	rt.Put("é", `back\slash`)
+	rt.Put("", "\t")
+
+	var b strings.Builder
+	if err := rt.WriteDOT(&b, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if !utf8.ValidString(out) {
+		t.Fatalf("graph is not valid UTF-8:\n%q", out)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if strings.ContainsFunc(strings.TrimPrefix(line, "\t"), unicode.IsControl) {
+			t.Fatalf("unescaped control character in line %q", line)
+		}
+	}
+	for _, want := range []string{
+		`n0 [label="root\n\\t"];`,
+		`[label="\\x00b"];`,
+		`[label="\\xffc"];`,
+		`[label="line\\nbreak"];`,
+		`[label="é"];`,
+		`[label="back\\slash"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in graph:\n%s", want, out)
+		}
+	}
+}
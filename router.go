@@ -0,0 +1,191 @@
+package radixtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Router matches URL-style paths against route patterns, returning the value
+// stored for the matching pattern along with the values of its parameters.
+//
+// A pattern is a '/'-separated path in which a segment may be a named
+// parameter or a catch-all:
+//
+//	/users/:id/posts    ":id" matches any single non-empty segment
+//	/static/*filepath   "*filepath" matches the rest of the path, if last
+//
+// When more than one pattern matches a path, a static segment takes priority
+// over a parameter, and a parameter takes priority over a catch-all.
+//
+// Patterns are stored in a Tree, with each parameter and catch-all segment
+// replaced by a single ':' or '*' byte, so patterns share storage for common
+// prefixes and matching descends the tree without copying the path.
+type Router[T any] struct {
+	tree Tree[*route[T]]
+}
+
+type route[T any] struct {
+	value T
+	names []string
+}
+
+// Param is a route parameter name and the path segment that it matched.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is a list of matched route parameters, in the order that they
+// appear in the route pattern.
+type Params []Param
+
+// Get returns the value of the named parameter, or an empty string if there is
+// no parameter with the name.
+func (ps Params) Get(name string) string {
+	for i := range ps {
+		if ps[i].Key == name {
+			return ps[i].Value
+		}
+	}
+	return ""
+}
+
+// NewRouter creates a new path router.
+func NewRouter[T any]() *Router[T] {
+	return new(Router[T])
+}
+
+// Len returns the number of route patterns stored in the router.
+func (r *Router[T]) Len() int {
+	return r.tree.Len()
+}
+
+// Add stores the value for the route pattern, replacing the value of any
+// existing pattern that differs only in parameter names. Returns an error if
+// the pattern has a parameter or catch-all without a name, or a catch-all
+// that is not the last segment.
+func (r *Router[T]) Add(pattern string, value T) error {
+	key, names, err := parseRoute(pattern)
+	if err != nil {
+		return err
+	}
+	r.tree.Put(key, &route[T]{
+		value: value,
+		names: names,
+	})
+	return nil
+}
+
+// Remove removes the route pattern. Parameter names are ignored, so any stored
+// pattern that differs only in parameter names is removed. Returns true if the
+// pattern was stored.
+func (r *Router[T]) Remove(pattern string) bool {
+	key, _, err := parseRoute(pattern)
+	if err != nil {
+		return false
+	}
+	return r.tree.Delete(key)
+}
+
+// Lookup finds the route pattern that matches the path, and returns its value
+// and parameters. The matched parameters are appended to params, and the
+// resulting slice returned, so that passing a slice with enough capacity
+// avoids allocation. Returns false if no pattern matches the path.
+func (r *Router[T]) Lookup(path string, params Params) (T, Params, bool) {
+	n := len(params)
+	rt := matchRoute(nodePos[*route[T]]{node: &r.tree.root}, path, &params, true)
+	if rt == nil {
+		var zero T
+		return zero, params[:n], false
+	}
+	for i, name := range rt.names {
+		params[n+i].Key = name
+	}
+	return rt.value, params, true
+}
+
+// parseRoute converts a route pattern into a tree key and a list of parameter
+// names.
+func parseRoute(pattern string) (string, []string, error) {
+	var (
+		b     strings.Builder
+		names []string
+	)
+	b.Grow(len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if (c != ':' && c != '*') || (i != 0 && pattern[i-1] != '/') {
+			b.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(pattern[i:], '/')
+		if end == -1 {
+			end = len(pattern)
+		} else {
+			if c == '*' {
+				return "", nil, fmt.Errorf("radixtree: catch-all must be last segment in route %q", pattern)
+			}
+			end += i
+		}
+		if end == i+1 {
+			return "", nil, fmt.Errorf("radixtree: unnamed parameter in route %q", pattern)
+		}
+		names = append(names, pattern[i+1:end])
+		b.WriteByte(c)
+		i = end - 1
+	}
+	return b.String(), names, nil
+}
+
+// matchRoute matches the remaining path from the tree position, appending
+// parameter values to params. At the start of each segment, a static match is
+// tried first, then a parameter, then a catch-all, backtracking as needed.
+func matchRoute[T any](pos nodePos[*route[T]], path string, params *Params, segStart bool) *route[T] {
+	for !segStart {
+		if len(path) == 0 {
+			return pos.value()
+		}
+		var ok bool
+		if pos, ok = pos.next(path[0]); !ok {
+			return nil
+		}
+		segStart = path[0] == '/'
+		path = path[1:]
+	}
+
+	// Static segment.
+	if len(path) == 0 {
+		if rt := pos.value(); rt != nil {
+			return rt
+		}
+	} else if path[0] != ':' && path[0] != '*' {
+		if next, ok := pos.next(path[0]); ok {
+			if rt := matchRoute(next, path[1:], params, path[0] == '/'); rt != nil {
+				return rt
+			}
+		}
+	}
+
+	// Parameter segment.
+	if next, ok := pos.next(':'); ok && len(path) != 0 && path[0] != '/' {
+		end := strings.IndexByte(path, '/')
+		if end == -1 {
+			end = len(path)
+		}
+		n := len(*params)
+		*params = append(*params, Param{Value: path[:end]})
+		if rt := matchRoute(next, path[end:], params, false); rt != nil {
+			return rt
+		}
+		*params = (*params)[:n]
+	}
+
+	// Catch-all segment.
+	if next, ok := pos.next('*'); ok {
+		if rt := next.value(); rt != nil {
+			*params = append(*params, Param{Value: path})
+			return rt
+		}
+	}
+	return nil
+}
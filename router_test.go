@@ -0,0 +1,98 @@
+package radixtree
+
+import (
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter[string]()
+	routes := []string{
+		"/",
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/posts",
+		"/users/:id/posts/:post",
+		"/users/:uid/files/*path",
+		"/static/*filepath",
+		"/static/index.html",
+		"/a:b/*rest",
+		"/*all",
+	}
+	for _, rt := range routes {
+		if err := r.Add(rt, rt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Len() != len(routes) {
+		t.Fatalf("expected %d routes, got %d", len(routes), r.Len())
+	}
+
+	tests := []struct {
+		path   string
+		route  string
+		params Params
+	}{
+		{"/", "/", nil},
+		{"/users", "/users", nil},
+		{"/users/new", "/users/new", nil},
+		{"/users/42", "/users/:id", Params{{"id", "42"}}},
+		{"/users/newer", "/users/:id", Params{{"id", "newer"}}},
+		{"/users/new/posts", "/users/:id/posts", Params{{"id", "new"}}},
+		{"/users/42/posts/7", "/users/:id/posts/:post", Params{{"id", "42"}, {"post", "7"}}},
+		{"/users/42/files/a/b.txt", "/users/:uid/files/*path", Params{{"uid", "42"}, {"path", "a/b.txt"}}},
+		{"/users/42/files/", "/users/:uid/files/*path", Params{{"uid", "42"}, {"path", ""}}},
+		{"/static/index.html", "/static/index.html", nil},
+		{"/static/css/site.css", "/static/*filepath", Params{{"filepath", "css/site.css"}}},
+		{"/a:b/c", "/a:b/*rest", Params{{"rest", "c"}}},
+		{"/users/", "/*all", Params{{"all", "users/"}}},
+		{"/users/42/other", "/*all", Params{{"all", "users/42/other"}}},
+		{"/:id", "/*all", Params{{"all", ":id"}}},
+	}
+	params := make(Params, 0, 4)
+	for _, tc := range tests {
+		val, ps, ok := r.Lookup(tc.path, params[:0])
+		if !ok || val != tc.route {
+			t.Errorf("path %q: expected route %q, got %q", tc.path, tc.route, val)
+			continue
+		}
+		if len(ps) != len(tc.params) {
+			t.Errorf("path %q: expected params %v, got %v", tc.path, tc.params, ps)
+			continue
+		}
+		for i := range ps {
+			if ps[i] != tc.params[i] {
+				t.Errorf("path %q: expected params %v, got %v", tc.path, tc.params, ps)
+				break
+			}
+		}
+	}
+
+	val, ps, ok := r.Lookup("/users/42/posts/7", nil)
+	if !ok || ps.Get("post") != "7" || ps.Get("id") != "42" || ps.Get("x") != "" {
+		t.Fatal("wrong params", val, ps)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Lookup("/users/42/files/a/b.txt", params[:0])
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+
+	if !r.Remove("/*x") {
+		t.Fatal("expected catch-all route to be removed")
+	}
+	if _, ps, ok = r.Lookup("/users/42/other", params[:0]); ok || len(ps) != 0 {
+		t.Fatal("expected no match after removing catch-all")
+	}
+	if r.Remove("/nope") || r.Remove("/*") {
+		t.Fatal("should not remove route that does not exist")
+	}
+
+	for _, bad := range []string{"/users/:", "/static/*", "/static/*path/x", "/:/x"} {
+		if err := r.Add(bad, ""); err == nil {
+			t.Errorf("expected error adding route %q", bad)
+		}
+	}
+}
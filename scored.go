@@ -0,0 +1,179 @@
+package radixtree
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+)
+
+// ScoredTree is a radix tree in which each value has a score, such as the
+// popularity of a search term, for autocompletion. The best score in the
+// subtree of each node is cached, so that TopK finds the highest-scored
+// completions of a prefix without visiting every key that has the prefix. The
+// cache is held by the ScoredTree, so the nodes of other trees do not pay for
+// it.
+type ScoredTree[T any] struct {
+	tree Tree[scored[T]]
+	best subtreeAggs[scored[T], float64]
+}
+
+type scored[T any] struct {
+	value T
+	score float64
+}
+
+// Scored is a key, value, and score returned by TopK.
+type Scored[T any] struct {
+	Key   string
+	Value T
+	Score float64
+}
+
+// NewScoredTree creates a new scored radix tree.
+func NewScoredTree[T any]() *ScoredTree[T] {
+	return &ScoredTree[T]{
+		best: subtreeAggs[scored[T], float64]{
+			fromItem: func(item *Item[scored[T]]) float64 { return item.value.score },
+			combine:  math.Max,
+		},
+	}
+}
+
+// Len returns the number of values stored in the tree.
+func (t *ScoredTree[T]) Len() int {
+	return t.tree.Len()
+}
+
+// Get returns the value and score stored at the given key. Returns false if
+// there is no value present for the key.
+func (t *ScoredTree[T]) Get(key string) (T, float64, bool) {
+	s, ok := t.tree.Get(key)
+	return s.value, s.score, ok
+}
+
+// Put inserts the value into the tree at the given key with the given score,
+// replacing any existing value and score. It returns true if it adds a new
+// value, false if it replaces an existing value. Panics if score is NaN.
+func (t *ScoredTree[T]) Put(key string, value T, score float64) bool {
+	if math.IsNaN(score) {
+		panic("radixtree: score is NaN")
+	}
+	isNew := t.tree.Put(key, scored[T]{
+		value: value,
+		score: score,
+	})
+	t.best.update(&t.tree, key)
+	return isNew
+}
+
+// Delete removes the value associated with the given key. Returns true if
+// there was a value stored for the key.
+func (t *ScoredTree[T]) Delete(key string) bool {
+	t.best.forget(&t.tree, key, false)
+	ok := t.tree.Delete(key)
+	t.best.update(&t.tree, key)
+	return ok
+}
+
+// DeletePrefix removes all values whose key is prefixed by the given prefix.
+// Returns true if any values were removed.
+func (t *ScoredTree[T]) DeletePrefix(prefix string) bool {
+	t.best.forget(&t.tree, prefix, true)
+	ok := t.tree.DeletePrefix(prefix)
+	t.best.update(&t.tree, prefix)
+	return ok
+}
+
+// Iter visits all values in the tree, yielding the key and value of each.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (t *ScoredTree[T]) Iter() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		for key, s := range t.tree.Iter() {
+			if !yield(key, s.value) {
+				return
+			}
+		}
+	}
+}
+
+// TopK returns up to k of the highest-scored values whose keys match or are
+// prefixed by the given prefix, in descending order of score. Values with
+// equal scores are returned in lexical order of their keys.
+//
+// After reaching the prefix, subtrees are visited best first, using the best
+// score cached for each, so that only the subtrees that contain the results
+// and their siblings are visited.
+func (t *ScoredTree[T]) TopK(prefix string, k int) []Scored[T] {
+	if k <= 0 {
+		return nil
+	}
+	node, _ := t.tree.locate(prefix)
+	if node == nil {
+		return nil
+	}
+	best, ok := t.best.get(node)
+	if !ok {
+		return nil
+	}
+
+	var out []Scored[T]
+	h := topKHeap[T]{{node: node, score: best}}
+	for len(h) != 0 && len(out) < k {
+		e := heap.Pop(&h).(topKEntry[T])
+		if e.item != nil {
+			out = append(out, Scored[T]{
+				Key:   e.item.key,
+				Value: e.item.value.value,
+				Score: e.score,
+			})
+			continue
+		}
+		if leaf := e.node.leaf; leaf != nil {
+			heap.Push(&h, topKEntry[T]{item: leaf, score: leaf.value.score})
+		}
+		for _, child := range e.node.nodes {
+			best, _ = t.best.get(child)
+			heap.Push(&h, topKEntry[T]{node: child, score: best})
+		}
+	}
+	return out
+}
+
+// topKEntry is either an item or a subtree, with the best score in it.
+type topKEntry[T any] struct {
+	node  *radixNode[scored[T]]
+	item  *Item[scored[T]]
+	score float64
+}
+
+// topKHeap orders entries by descending score. A subtree is ordered before an
+// item with an equal score, since it may contain an item with the same score
+// and a lower key, and items with equal scores are ordered by key.
+type topKHeap[T any] []topKEntry[T]
+
+func (h topKHeap[T]) Len() int { return len(h) }
+
+func (h topKHeap[T]) Less(i, j int) bool {
+	a, b := &h[i], &h[j]
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	if (a.item == nil) != (b.item == nil) {
+		return a.item == nil
+	}
+	return a.item != nil && a.item.key < b.item.key
+}
+
+func (h topKHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *topKHeap[T]) Push(x any) { *h = append(*h, x.(topKEntry[T])) }
+
+func (h *topKHeap[T]) Pop() any {
+	old := *h
+	n := len(old) - 1
+	e := old[n]
+	old[n] = topKEntry[T]{}
+	*h = old[:n]
+	return e
+}
@@ -0,0 +1,192 @@
+package radixtree
+
+import (
+	"cmp"
+	"iter"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScoredTreeTopK(t *testing.T) {
+	st := NewScoredTree[int]()
+	st.Put("tom", 1, 5)
+	st.Put("tomato", 2, 9)
+	st.Put("tomb", 3, 7)
+	st.Put("torn", 4, 9)
+	st.Put("tornado", 5, 1)
+	st.Put("apple", 6, 100)
+
+	got := st.TopK("to", 3)
+	want := []Scored[int]{
+		{Key: "tomato", Value: 2, Score: 9},
+		{Key: "torn", Value: 4, Score: 9},
+		{Key: "tomb", Value: 3, Score: 7},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got = st.TopK("tom", 10); len(got) != 3 || got[2].Key != "tom" {
+		t.Fatalf("expected 3 results ending with tom, got %v", got)
+	}
+	if got = st.TopK("x", 3); len(got) != 0 {
+		t.Fatalf("expected no results, got %v", got)
+	}
+	if got = st.TopK("", 0); len(got) != 0 {
+		t.Fatalf("expected no results, got %v", got)
+	}
+
+	// Changing a score is reflected in the cached best scores.
+	st.Put("tornado", 5, 50)
+	if got = st.TopK("t", 1); got[0].Key != "tornado" {
+		t.Fatalf("expected tornado, got %v", got)
+	}
+	st.Delete("tornado")
+	if got = st.TopK("t", 1); got[0].Key != "tomato" {
+		t.Fatalf("expected tomato, got %v", got)
+	}
+	st.DeletePrefix("tom")
+	if got = st.TopK("", 5); len(got) != 2 || got[0].Key != "apple" || got[1].Key != "torn" {
+		t.Fatalf("expected apple and torn, got %v", got)
+	}
+	val, score, ok := st.Get("torn")
+	if !ok || val != 4 || score != 9 {
+		t.Fatalf("unexpected Get result %d %f %t", val, score, ok)
+	}
+}
+
+func TestScoredTreeDeletePrefixEmpty(t *testing.T) {
+	st := NewScoredTree[int]()
+	if st.DeletePrefix("") || st.Len() != 0 {
+		t.Fatalf("DeletePrefix on empty tree removed values, length %d", st.Len())
+	}
+	st.Put("a", 1, 1)
+	st.Put("b", 2, 2)
+	if !st.DeletePrefix("") || st.Len() != 0 || len(st.TopK("", 1)) != 0 {
+		t.Fatal("DeletePrefix did not remove all values")
+	}
+	if st.DeletePrefix("") || st.Len() != 0 {
+		t.Fatalf("DeletePrefix on emptied tree removed values, length %d", st.Len())
+	}
+}
+
+func TestScoredTreeRandom(t *testing.T) {
+	st := NewScoredTree[int]()
+	ref := map[string]float64{}
+	rnd := rand.New(rand.NewSource(1))
+	randKey := func() string {
+		return strconv.FormatInt(rnd.Int63n(5000), 4)
+	}
+
+	check := func(prefix string, k int) {
+		var want []Scored[int]
+		for key, score := range ref {
+			if strings.HasPrefix(key, prefix) {
+				want = append(want, Scored[int]{Key: key, Value: len(key), Score: score})
+			}
+		}
+		slices.SortFunc(want, func(a, b Scored[int]) int {
+			if c := cmp.Compare(b.Score, a.Score); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Key, b.Key)
+		})
+		want = want[:min(k, len(want))]
+		got := st.TopK(prefix, k)
+		if !slices.Equal(got, want) {
+			t.Fatalf("TopK(%q, %d): expected %v, got %v", prefix, k, want, got)
+		}
+	}
+
+	for i := range 20000 {
+		key := randKey()
+		switch rnd.Intn(10) {
+		case 0, 1, 2:
+			st.Delete(key)
+			delete(ref, key)
+		case 3:
+			key = key[:len(key)/2]
+			st.DeletePrefix(key)
+			for k := range ref {
+				if strings.HasPrefix(k, key) {
+					delete(ref, k)
+				}
+			}
+		default:
+			score := float64(rnd.Intn(100))
+			st.Put(key, len(key), score)
+			ref[key] = score
+		}
+		if st.Len() != len(ref) {
+			t.Fatalf("expected size %d, got %d", len(ref), st.Len())
+		}
+		if i%50 == 0 {
+			prefix := randKey()
+			check(prefix[:rnd.Intn(len(prefix)+1)], 1+rnd.Intn(10))
+		}
+		if i%1000 == 0 {
+			checkAggs(t, &st.tree, &st.best)
+		}
+	}
+	check("", len(ref))
+	checkAggs(t, &st.tree, &st.best)
+}
+
+// checkAggs checks that the aggregate kept for each node in the tree is the
+// aggregate of the items in the node's subtree, and that no aggregates are kept
+// for nodes that have been removed from the tree.
+func checkAggs[T any, A comparable](t *testing.T, tree *Tree[T], s *subtreeAggs[T, A]) {
+	t.Helper()
+	inTree := make(map[*radixNode[T]]bool)
+	var walk func(*radixNode[T])
+	walk = func(node *radixNode[T]) {
+		inTree[node] = true
+		if a, ok := s.aggs[node]; ok {
+			var (
+				want A
+				has  bool
+			)
+			for item := range node.items() {
+				if has {
+					want = s.combine(want, s.fromItem(item))
+				} else {
+					want, has = s.fromItem(item), true
+				}
+			}
+			if !has || a != want {
+				t.Fatalf("node %q has aggregate %v, expected %v", node.prefix, a, want)
+			}
+		}
+		for _, child := range node.nodes {
+			walk(child)
+		}
+	}
+	walk(&tree.root)
+	for node := range s.aggs {
+		if !inTree[node] {
+			t.Fatalf("aggregate kept for removed node %q", node.prefix)
+		}
+	}
+}
+
+// items visits the items in the node's subtree in lexical order.
+func (node *radixNode[T]) items() iter.Seq[*Item[T]] {
+	return func(yield func(*Item[T]) bool) {
+		var walk func(*radixNode[T]) bool
+		walk = func(n *radixNode[T]) bool {
+			if n.leaf != nil && !yield(n.leaf) {
+				return false
+			}
+			for _, child := range n.nodes {
+				if !walk(child) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(node)
+	}
+}
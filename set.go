@@ -0,0 +1,194 @@
+package radixtree
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Set is an ordered set of strings, stored in a radix tree so that strings
+// with a common prefix share node structure.
+//
+// A Set uses the node logic of Tree, but stores no Item for each key. A node
+// that ends a key points to a single shared marker instead, so adding a key
+// allocates only the nodes that it needs. Keys are rebuilt from the path to
+// their nodes during iteration, which allocates each key that is yielded.
+type Set struct {
+	tree Tree[struct{}]
+}
+
+// setMember is the leaf of every node that ends a key in a Set. It holds no
+// key, since keys are rebuilt from the path to the node.
+var setMember = &Item[struct{}]{}
+
+// NewSet creates a new empty set.
+func NewSet() *Set {
+	return new(Set)
+}
+
+// Len returns the number of keys in the set.
+func (s *Set) Len() int {
+	return s.tree.Len()
+}
+
+// Add adds the key to the set. Returns true if the key was not already in the
+// set.
+func (s *Set) Add(key string) bool {
+	return s.tree.put(key, setMember)
+}
+
+// Has returns true if the key is in the set.
+func (s *Set) Has(key string) bool {
+	_, ok := s.tree.Get(key)
+	return ok
+}
+
+// Remove removes the key from the set. Returns true if the key was in the set.
+func (s *Set) Remove(key string) bool {
+	return s.tree.Delete(key)
+}
+
+// RemovePrefix removes all keys that are prefixed by the given prefix. Returns
+// true if any keys were removed.
+func (s *Set) RemovePrefix(prefix string) bool {
+	return s.tree.DeletePrefix(prefix)
+}
+
+// All visits all keys in the set.
+//
+// The set is traversed in lexical order, making the output deterministic.
+func (s *Set) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		setKeys(&s.tree.root, nil, yield)
+	}
+}
+
+// WithPrefix visits all keys that match or are prefixed by the given prefix.
+//
+// The set is traversed in lexical order, making the output deterministic.
+func (s *Set) WithPrefix(prefix string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		node, rem := s.tree.locate(prefix)
+		if node == nil {
+			return
+		}
+		setKeys(node, []byte(prefix+rem), yield)
+	}
+}
+
+// PathTo visits each key that is a prefix of, or equal to, the given key, from
+// shortest to longest.
+func (s *Set) PathTo(key string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		node := &s.tree.root
+		var n int
+		for {
+			if node.leaf != nil && !yield(key[:n]) {
+				return
+			}
+			if n == len(key) {
+				return
+			}
+			if node = node.getEdge(key[n]); node == nil {
+				return
+			}
+			n++
+			if !strings.HasPrefix(key[n:], node.prefix) {
+				return
+			}
+			n += len(node.prefix)
+		}
+	}
+}
+
+// Clone returns a copy of the set.
+func (s *Set) Clone() *Set {
+	c := &Set{}
+	c.tree.root = *cloneSetNode(&s.tree.root)
+	c.tree.size = s.tree.size
+	return c
+}
+
+// Union returns a new set containing the keys that are in either s or other.
+func (s *Set) Union(other *Set) *Set {
+	if other.Len() > s.Len() {
+		s, other = other, s
+	}
+	u := s.Clone()
+	for key := range other.All() {
+		u.Add(key)
+	}
+	return u
+}
+
+// Intersection returns a new set containing the keys that are in both s and
+// other.
+func (s *Set) Intersection(other *Set) *Set {
+	if other.Len() < s.Len() {
+		s, other = other, s
+	}
+	in := NewSet()
+	for key := range s.All() {
+		if other.Has(key) {
+			in.Add(key)
+		}
+	}
+	return in
+}
+
+// Difference returns a new set containing the keys that are in s but not in
+// other.
+func (s *Set) Difference(other *Set) *Set {
+	diff := NewSet()
+	for key := range s.All() {
+		if !other.Has(key) {
+			diff.Add(key)
+		}
+	}
+	return diff
+}
+
+// IsSubset returns true if every key in s is also in other.
+func (s *Set) IsSubset(other *Set) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for key := range s.All() {
+		if !other.Has(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// setKeys yields the keys in the node's subtree, where key is the key of the
+// node, rebuilt from the path to it. Returns false if iteration was stopped.
+func setKeys(node *radixNode[struct{}], key []byte, yield func(string) bool) bool {
+	if node.leaf != nil && !yield(string(key)) {
+		return false
+	}
+	for i, child := range node.nodes {
+		next := append(append(key, node.radices[i]), child.prefix...)
+		if !setKeys(child, next, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneSetNode recursively copies the node and its descendants, sharing the
+// member marker rather than copying an item for each key.
+func cloneSetNode(node *radixNode[struct{}]) *radixNode[struct{}] {
+	cp := &radixNode[struct{}]{
+		prefix:  node.prefix,
+		radices: slices.Clone(node.radices),
+		leaf:    node.leaf,
+	}
+	if node.nodes != nil {
+		cp.nodes = make([]*radixNode[struct{}], len(node.nodes))
+		for i, child := range node.nodes {
+			cp.nodes[i] = cloneSetNode(child)
+		}
+	}
+	return cp
+}
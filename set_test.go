@@ -0,0 +1,193 @@
+package radixtree
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet()
+	for _, key := range []string{"tom", "tomato", "torn", "to", ""} {
+		if !s.Add(key) {
+			t.Fatalf("expected %q to be added", key)
+		}
+	}
+	if s.Add("tom") {
+		t.Fatal("expected tom to already be in set")
+	}
+	if s.Len() != 5 {
+		t.Fatalf("expected 5 keys, got %d", s.Len())
+	}
+	if !s.Has("tom") || !s.Has("") || s.Has("toma") {
+		t.Fatal("wrong result from Has")
+	}
+
+	got := slices.Collect(s.All())
+	want := []string{"", "to", "tom", "tomato", "torn"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	got = slices.Collect(s.WithPrefix("tom"))
+	want = []string{"tom", "tomato"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	got = slices.Collect(s.PathTo("tomatoes"))
+	want = []string{"", "to", "tom", "tomato"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if !s.Remove("to") || s.Remove("to") {
+		t.Fatal("wrong result from Remove")
+	}
+	if !s.RemovePrefix("tom") || s.Has("tomato") || s.Len() != 2 {
+		t.Fatal("RemovePrefix did not remove keys")
+	}
+}
+
+func TestSetRemovePrefixEmpty(t *testing.T) {
+	s := NewSet()
+	if s.RemovePrefix("") || s.Len() != 0 {
+		t.Fatalf("RemovePrefix on empty set removed keys, length %d", s.Len())
+	}
+	s.Add("a")
+	s.Add("b")
+	if !s.RemovePrefix("") || s.Len() != 0 {
+		t.Fatal("RemovePrefix did not remove all keys")
+	}
+	if s.RemovePrefix("") || s.RemovePrefix("a") || s.Len() != 0 {
+		t.Fatalf("RemovePrefix on emptied set removed keys, length %d", s.Len())
+	}
+	s.Add("a")
+	if s.Len() != 1 || !s.Has("a") {
+		t.Fatal("expected 1 key after emptying set")
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a, b := NewSet(), NewSet()
+	for _, key := range []string{"a", "ab", "abc", "b"} {
+		a.Add(key)
+	}
+	for _, key := range []string{"ab", "b", "bc", "c"} {
+		b.Add(key)
+	}
+
+	check := func(name string, s *Set, want ...string) {
+		t.Helper()
+		got := slices.Collect(s.All())
+		if !slices.Equal(got, want) {
+			t.Fatalf("%s: expected %q, got %q", name, want, got)
+		}
+		if s.Len() != len(want) {
+			t.Fatalf("%s: expected length %d, got %d", name, len(want), s.Len())
+		}
+	}
+	check("union", a.Union(b), "a", "ab", "abc", "b", "bc", "c")
+	check("intersection", a.Intersection(b), "ab", "b")
+	check("difference", a.Difference(b), "a", "abc")
+	check("difference", b.Difference(a), "bc", "c")
+
+	// Operands are not modified.
+	check("a", a, "a", "ab", "abc", "b")
+	check("b", b, "ab", "b", "bc", "c")
+
+	if a.IsSubset(b) || !a.Intersection(b).IsSubset(a) || !a.IsSubset(a.Union(b)) {
+		t.Fatal("wrong result from IsSubset")
+	}
+}
+
+func TestSetNoItems(t *testing.T) {
+	s := NewSet()
+	rnd := rand.New(rand.NewSource(1))
+	ref := make(map[string]bool)
+	for range 2000 {
+		b := make([]byte, rnd.Intn(6))
+		for i := range b {
+			b[i] = "abc/"[rnd.Intn(4)]
+		}
+		key := string(b)
+		if rnd.Intn(4) == 0 {
+			if s.Remove(key) != ref[key] {
+				t.Fatalf("wrong result removing %q", key)
+			}
+			delete(ref, key)
+			continue
+		}
+		if s.Add(key) == ref[key] {
+			t.Fatalf("wrong result adding %q", key)
+		}
+		ref[key] = true
+	}
+
+	var want []string
+	for key := range ref {
+		want = append(want, key)
+	}
+	slices.Sort(want)
+	if got := slices.Collect(s.All()); !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for _, prefix := range []string{"", "a", "ab", "c/b", "/a/"} {
+		var wantPrefix []string
+		for _, key := range want {
+			if strings.HasPrefix(key, prefix) {
+				wantPrefix = append(wantPrefix, key)
+			}
+		}
+		if got := slices.Collect(s.WithPrefix(prefix)); !slices.Equal(got, wantPrefix) {
+			t.Fatalf("prefix %q: expected %q, got %q", prefix, wantPrefix, got)
+		}
+	}
+
+	c := s.Clone()
+	for _, key := range want {
+		s.Remove(key)
+	}
+	if s.Len() != 0 || c.Len() != len(want) {
+		t.Fatal("clone is not independent of the original set")
+	}
+	if got := slices.Collect(c.All()); !slices.Equal(got, want) {
+		t.Fatalf("clone: expected %q, got %q", want, got)
+	}
+
+	// Every key shares the member marker instead of having its own item.
+	var walk func(*radixNode[struct{}])
+	walk = func(node *radixNode[struct{}]) {
+		if node.leaf != nil && node.leaf != setMember {
+			t.Fatalf("node %q has its own item", node.prefix)
+		}
+		for _, child := range node.nodes {
+			walk(child)
+		}
+	}
+	walk(&c.tree.root)
+}
+
+func TestSetAddAllocs(t *testing.T) {
+	s := NewSet()
+	rt := New[struct{}]()
+	for _, key := range []string{"tom", "tomato", "tomb"} {
+		s.Add(key)
+		rt.Put(key, struct{}{})
+	}
+	// Adding a key that ends at an existing node allocates nothing, where a
+	// Tree allocates an item.
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Remove("tom")
+		s.Add("tom")
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+	allocs = testing.AllocsPerRun(100, func() {
+		rt.Delete("tom")
+		rt.Put("tom", struct{}{})
+	})
+	if allocs != 1 {
+		t.Fatalf("expected 1 allocation by Tree, got %v", allocs)
+	}
+}
@@ -0,0 +1,77 @@
+package radixtree
+
+import "unsafe"
+
+// Stats describes the structure and estimated memory use of a tree or subtree.
+type Stats struct {
+	// Nodes is the number of nodes, including the root node.
+	Nodes int
+	// Leaves is the number of nodes that hold a value.
+	Leaves int
+	// MaxDepth is the greatest number of edges between the root and a node.
+	MaxDepth int
+	// AvgDepth is the average number of edges between the root and a node that
+	// holds a value.
+	AvgDepth float64
+	// Fanout is a histogram of the number of children of each node. Fanout[n]
+	// is the number of nodes that have n children.
+	Fanout []int
+	// PrefixBytes is the total length of the edge prefixes of all nodes.
+	PrefixBytes int
+	// KeyBytes is the total length of the keys of all values.
+	KeyBytes int
+	// EstimatedBytes is an estimate of the memory used by the nodes, their
+	// edge slices, and the items that hold the keys and values. Key bytes are
+	// included, but prefix bytes are not, since prefixes are mostly substrings
+	// of keys. Memory referenced by values is not included.
+	EstimatedBytes int
+}
+
+// Stats returns statistics about the structure and memory use of the tree.
+func (t *Tree[T]) Stats() Stats {
+	return t.root.stats()
+}
+
+// StatsAt returns statistics about the subtree containing all values whose
+// keys match or are prefixed by the given prefix. Depths are counted from the
+// root of the subtree. Returns a zero Stats if no keys have the prefix.
+func (t *Tree[T]) StatsAt(prefix string) Stats {
+	node, _ := t.locate(prefix)
+	if node == nil {
+		return Stats{}
+	}
+	return node.stats()
+}
+
+func (node *radixNode[T]) stats() Stats {
+	var (
+		st       Stats
+		depthSum int
+	)
+	node.collectStats(&st, 0, &depthSum)
+	if st.Leaves != 0 {
+		st.AvgDepth = float64(depthSum) / float64(st.Leaves)
+	}
+	return st
+}
+
+func (node *radixNode[T]) collectStats(st *Stats, depth int, depthSum *int) {
+	st.Nodes++
+	st.MaxDepth = max(st.MaxDepth, depth)
+	st.PrefixBytes += len(node.prefix)
+	st.EstimatedBytes += int(unsafe.Sizeof(*node)) + cap(node.radices) + cap(node.nodes)*int(unsafe.Sizeof(node))
+	if node.leaf != nil {
+		st.Leaves++
+		*depthSum += depth
+		st.KeyBytes += len(node.leaf.key)
+		st.EstimatedBytes += int(unsafe.Sizeof(*node.leaf)) + len(node.leaf.key)
+	}
+	fanout := len(node.nodes)
+	if fanout >= len(st.Fanout) {
+		st.Fanout = append(st.Fanout, make([]int, fanout+1-len(st.Fanout))...)
+	}
+	st.Fanout[fanout]++
+	for _, child := range node.nodes {
+		child.collectStats(st, depth+1, depthSum)
+	}
+}
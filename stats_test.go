@@ -0,0 +1,56 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	rt := New[int]()
+	if st := rt.Stats(); st.Nodes != 1 || st.Leaves != 0 || st.AvgDepth != 0 {
+		t.Fatalf("unexpected stats for empty tree: %+v", st)
+	}
+
+	// (root) t-> ("o", _) m-> ("", TOM) a-> ("to", TOMATO)
+	//                     r-> ("n", TORN)
+	rt.Put("tom", 1)
+	rt.Put("tomato", 2)
+	rt.Put("torn", 3)
+
+	st := rt.Stats()
+	if st.Nodes != 5 || st.Leaves != 3 || st.MaxDepth != 3 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	if st.AvgDepth != float64(2+3+2)/3 {
+		t.Fatalf("expected average depth %f, got %f", float64(7)/3, st.AvgDepth)
+	}
+	if want := []int{2, 2, 1}; !slices.Equal(st.Fanout, want) {
+		t.Fatalf("expected fanout %v, got %v", want, st.Fanout)
+	}
+	if st.PrefixBytes != 4 || st.KeyBytes != 13 {
+		t.Fatalf("expected 4 prefix bytes and 13 key bytes, got %d and %d", st.PrefixBytes, st.KeyBytes)
+	}
+	if st.EstimatedBytes <= st.KeyBytes {
+		t.Fatalf("estimated bytes %d too small", st.EstimatedBytes)
+	}
+
+	var nodes int
+	rt.Inspect(func(link, prefix, key string, depth, children int, hasValue bool, value int) bool {
+		nodes++
+		return false
+	})
+	if nodes != st.Nodes {
+		t.Fatalf("expected %d nodes from Inspect, got %d", nodes, st.Nodes)
+	}
+
+	sub := rt.StatsAt("tom")
+	if sub.Nodes != 2 || sub.Leaves != 2 || sub.MaxDepth != 1 || sub.AvgDepth != 0.5 {
+		t.Fatalf("unexpected subtree stats: %+v", sub)
+	}
+	if sub.EstimatedBytes >= st.EstimatedBytes {
+		t.Fatal("subtree estimate should be less than tree estimate")
+	}
+	if sub = rt.StatsAt("x"); sub.Nodes != 0 {
+		t.Fatalf("expected zero stats for missing prefix, got %+v", sub)
+	}
+}
@@ -1,18 +1,48 @@
 package radixtree
 
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
 // Stepper traverses a Tree one byte at a time.
 //
-// Any modification to the tree invalidates the Stepper.
+// A Stepper remembers the path it has taken, so that it can step back towards
+// the root of the tree without allocating. This supports backtracking searches
+// that would otherwise need to keep a copy of the Stepper at each position.
+//
+// Any modification to the tree invalidates the Stepper. Use Valid to check
+// whether the tree has been modified, or enable checked mode on the tree to
+// panic when an invalid Stepper is used.
 type Stepper[T any] struct {
-	p    int
-	node *radixNode[T]
+	p     int
+	depth int
+	node  *radixNode[T]
+	tree  *Tree[T]
+	gen   uint64
+	// next holds the byte returned by NextBytes when within a node's prefix.
+	next [1]byte
+	// parents holds the node that each edge traversed so far leads from, for
+	// the first edges, so that stepping does not allocate. Deeper edges are
+	// held in more. Positions within a node's prefix are recovered from p, so
+	// only one entry is needed per edge rather than per byte.
+	parents  [stepperParents]*radixNode[T]
+	more     []*radixNode[T]
+	nparents int
 }
 
+// stepperParents is the number of edges a Stepper can traverse before it
+// allocates to remember the path.
+const stepperParents = 16
+
 // NewStepper returns a new Stepper instance that begins at the root of the
 // tree.
 func (t *Tree[T]) NewStepper() *Stepper[T] {
 	return &Stepper[T]{
 		node: &t.root,
+		tree: t,
+		gen:  t.gen,
 	}
 }
 
@@ -20,9 +50,29 @@ func (t *Tree[T]) NewStepper() *Stepper[T] {
 // into two that can take separate paths. These Steppers do not affect each
 // other and can be used concurrently.
 func (s *Stepper[T]) Copy() *Stepper[T] {
+	s.check()
 	return &Stepper[T]{
-		p:    s.p,
-		node: s.node,
+		p:        s.p,
+		depth:    s.depth,
+		node:     s.node,
+		tree:     s.tree,
+		gen:      s.gen,
+		parents:  s.parents,
+		more:     slices.Clone(s.more),
+		nparents: s.nparents,
+	}
+}
+
+// Valid returns true if the tree has not been modified since the Stepper was
+// created or last reset.
+func (s *Stepper[T]) Valid() bool {
+	return s.gen == s.tree.gen
+}
+
+// check panics if the tree is in checked mode and the Stepper is not valid.
+func (s *Stepper[T]) check() {
+	if s.tree.checked && s.gen != s.tree.gen {
+		panic("radixtree: stepper used after tree modified")
 	}
 }
 
@@ -34,12 +84,14 @@ func (s *Stepper[T]) Copy() *Stepper[T] {
 // When false is returned the Stepper is not modified. This allows different
 // values to be used in subsequent calls to Next.
 func (s *Stepper[T]) Next(radix byte) bool {
+	s.check()
 	// The tree.prefix represents single-edge parents without values that were
 	// compressed out of the tree. Let prefix consume key symbols.
 	if s.p < len(s.node.prefix) {
 		if radix == s.node.prefix[s.p] {
 			// Key matches prefix so far, ok to continue.
 			s.p++
+			s.depth++
 			return true
 		}
 		// Some unmatched prefix remains, node not found.
@@ -51,14 +103,143 @@ func (s *Stepper[T]) Next(radix byte) bool {
 		return false
 	}
 	// Key symbol matched up to this edge, ok to continue.
+	s.pushParent(s.node)
 	s.p = 0
 	s.node = node
+	s.depth++
 	return true
 }
 
+// NextString advances the Stepper by as many bytes of key as match a path in
+// the tree, and returns the number of bytes consumed. The Stepper is left at
+// the furthest position reached, so if fewer than len(key) bytes are
+// consumed, then key[consumed] is the first byte that did not match.
+func (s *Stepper[T]) NextString(key string) (consumed int) {
+	s.check()
+	for len(key) != 0 {
+		if s.p < len(s.node.prefix) {
+			// Consume as much of the remaining prefix as matches the key.
+			rem := s.node.prefix[s.p:]
+			n := len(rem)
+			if !strings.HasPrefix(key, rem) {
+				n = commonPrefixLen(key, rem)
+			}
+			s.p += n
+			s.depth += n
+			consumed += n
+			if n < len(rem) {
+				return consumed
+			}
+			key = key[n:]
+			continue
+		}
+		node := s.node.getEdge(key[0])
+		if node == nil {
+			return consumed
+		}
+		s.pushParent(s.node)
+		s.p = 0
+		s.node = node
+		s.depth++
+		consumed++
+		key = key[1:]
+	}
+	return consumed
+}
+
+// NextPrefix advances the Stepper by all bytes of key, if all of key matches a
+// path in the tree, and returns true. Otherwise false is returned and the
+// Stepper is not modified.
+func (s *Stepper[T]) NextPrefix(key string) bool {
+	p, depth, node, n := s.p, s.depth, s.node, s.nparents
+	if s.NextString(key) == len(key) {
+		return true
+	}
+	for s.nparents > n {
+		s.popParent()
+	}
+	s.p, s.depth, s.node = p, depth, node
+	return false
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// Back moves the Stepper back to the position it was at before the last
+// successful call to Next. Returns false if the Stepper is already at the root
+// of the tree.
+func (s *Stepper[T]) Back() bool {
+	s.check()
+	if s.p != 0 {
+		s.p--
+	} else if s.nparents != 0 {
+		// Back up over the edge into the parent node.
+		s.node = s.popParent()
+		s.p = len(s.node.prefix)
+	} else {
+		return false
+	}
+	s.depth--
+	return true
+}
+
+// Reset moves the Stepper back to the root of the tree. This makes the Stepper
+// valid again after the tree has been modified.
+func (s *Stepper[T]) Reset() {
+	clear(s.parents[:])
+	clear(s.more)
+	s.more = s.more[:0]
+	s.nparents = 0
+	s.node = &s.tree.root
+	s.p = 0
+	s.depth = 0
+	s.gen = s.tree.gen
+}
+
+// pushParent records the node that the edge being traversed leads from.
+func (s *Stepper[T]) pushParent(node *radixNode[T]) {
+	if s.nparents < stepperParents {
+		s.parents[s.nparents] = node
+	} else {
+		s.more = append(s.more, node)
+	}
+	s.nparents++
+}
+
+// popParent removes and returns the node that the last edge traversed leads
+// from.
+func (s *Stepper[T]) popParent() *radixNode[T] {
+	s.nparents--
+	if s.nparents < stepperParents {
+		node := s.parents[s.nparents]
+		s.parents[s.nparents] = nil
+		return node
+	}
+	i := len(s.more) - 1
+	node := s.more[i]
+	s.more[i] = nil
+	s.more = s.more[:i]
+	return node
+}
+
+// Depth returns the number of key bytes consumed to reach the current Stepper
+// position. This is the number of calls to Back needed to return to the root.
+func (s *Stepper[T]) Depth() int {
+	return s.depth
+}
+
 // Item returns an Item containing the key and value at the current Stepper
 // position, or returns nil if no value is present at the position.
 func (s *Stepper[T]) Item() *Item[T] {
+	s.check()
 	// Only return item if all of this node's prefix was matched. Otherwise,
 	// have not fully traversed into this node (edge not completely traversed).
 	if s.p == len(s.node.prefix) {
@@ -77,3 +258,86 @@ func (s *Stepper[T]) Value() (T, bool) {
 	}
 	return item.value, true
 }
+
+// Key returns the key bytes consumed to reach the current Stepper position.
+func (s *Stepper[T]) Key() string {
+	s.check()
+	if s.depth == 0 {
+		return ""
+	}
+	// Every node other than the root has a value in its subtree, and the key of
+	// any value below the position starts with the consumed key.
+	return s.node.firstItem().key[:s.depth]
+}
+
+// NextBytes returns the bytes for which Next would advance the Stepper from its
+// current position, in ascending order. The returned slice must not be
+// modified, and is only valid until the Stepper or tree is next modified.
+func (s *Stepper[T]) NextBytes() []byte {
+	s.check()
+	if s.p < len(s.node.prefix) {
+		s.next[0] = s.node.prefix[s.p]
+		return s.next[:]
+	}
+	return slices.Clip(s.node.radices)
+}
+
+// IsPrefix returns true if the key consumed to reach the current Stepper
+// position is a prefix of a longer key in the tree. This means that Next can
+// advance the Stepper further.
+func (s *Stepper[T]) IsPrefix() bool {
+	s.check()
+	return s.p < len(s.node.prefix) || len(s.node.radices) != 0
+}
+
+// Iter visits all nodes whose keys are prefixed by the key consumed to reach
+// the current Stepper position, yielding the key and value of each.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (s *Stepper[T]) Iter() iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		s.check()
+		s.node.walk(checkedYield(s.tree, yield))
+	}
+}
+
+// nodePos is a position in the tree, the same as that of a Stepper, for
+// searches that branch and backtrack by copying positions by value.
+type nodePos[T any] struct {
+	node *radixNode[T]
+	p    int
+}
+
+// next returns the position reached by consuming radix, and false if no key in
+// the tree continues with radix.
+func (pos nodePos[T]) next(radix byte) (nodePos[T], bool) {
+	if pos.p < len(pos.node.prefix) {
+		if pos.node.prefix[pos.p] == radix {
+			pos.p++
+			return pos, true
+		}
+		return pos, false
+	}
+	node := pos.node.getEdge(radix)
+	if node == nil {
+		return pos, false
+	}
+	return nodePos[T]{node: node}, true
+}
+
+// item returns the Item at the position, or nil if there is none.
+func (pos nodePos[T]) item() *Item[T] {
+	if pos.p == len(pos.node.prefix) {
+		return pos.node.leaf
+	}
+	return nil
+}
+
+// value returns the value at the position, or the zero value if there is none.
+func (pos nodePos[T]) value() T {
+	if item := pos.item(); item != nil {
+		return item.value
+	}
+	var zero T
+	return zero
+}
@@ -1,6 +1,7 @@
 package radixtree
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -100,3 +101,330 @@ func TestStepper(t *testing.T) {
 		t.Fatal("'x' should not have advanced iterator")
 	}
 }
+
+func TestStepperBack(t *testing.T) {
+	rt := new(Tree[string])
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("torn", "TORN")
+
+	// (root) t-> ("o", _) m-> ("", TOM) a-> ("to", TOMATO)
+	//                     r-> ("n", TORN)
+
+	s := rt.NewStepper()
+	if s.Back() {
+		t.Fatal("should not back up from root")
+	}
+	if s.Depth() != 0 {
+		t.Fatal("expected depth 0 at root")
+	}
+	for i, c := range []byte("tomato") {
+		if !s.Next(c) {
+			t.Fatalf("%q should have advanced stepper", c)
+		}
+		if s.Depth() != i+1 {
+			t.Fatalf("expected depth %d, got %d", i+1, s.Depth())
+		}
+	}
+	if val, _ := s.Value(); val != "TOMATO" {
+		t.Fatal("expected TOMATO")
+	}
+
+	// Back up to "tom".
+	for range 3 {
+		if !s.Back() {
+			t.Fatal("should have backed up")
+		}
+	}
+	if s.Depth() != 3 {
+		t.Fatalf("expected depth 3, got %d", s.Depth())
+	}
+	if val, _ := s.Value(); val != "TOM" {
+		t.Fatal("expected TOM after backing up")
+	}
+
+	// Back up to "to" and take the other branch.
+	if !s.Back() {
+		t.Fatal("should have backed up")
+	}
+	if s.Item() != nil {
+		t.Fatal("should not have item at \"to\"")
+	}
+	if s.Next('x') {
+		t.Fatal("'x' should not have advanced stepper")
+	}
+	if !s.Next('r') || !s.Next('n') {
+		t.Fatal("\"rn\" should have advanced stepper")
+	}
+	if val, _ := s.Value(); val != "TORN" {
+		t.Fatal("expected TORN")
+	}
+
+	// Copy must not share the position stack.
+	cp := s.Copy()
+	s.Reset()
+	if s.Depth() != 0 || s.Back() {
+		t.Fatal("expected stepper at root after reset")
+	}
+	if !s.Next('t') {
+		t.Fatal("'t' should have advanced stepper")
+	}
+	if val, _ := cp.Value(); val != "TORN" || cp.Depth() != 4 {
+		t.Fatal("copy affected by reset")
+	}
+	for cp.Back() {
+	}
+	if cp.Depth() != 0 || !cp.Next('t') {
+		t.Fatal("expected copy to back up to root")
+	}
+
+	s.Reset()
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, c := range []byte("tomato") {
+			s.Next(c)
+		}
+		for s.Back() {
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestStepperNextAllocs(t *testing.T) {
+	rt := new(Tree[int])
+	for _, key := range []string{"a", "ab", "abc", "abcd", "abcde", "abcdef", "abcdefg", "abcdefgh"} {
+		rt.Put(key, len(key))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		s := rt.NewStepper()
+		for _, c := range []byte("abcdefgh") {
+			if !s.Next(c) {
+				t.Fatal("stepper did not advance")
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+
+	// Paths with more edges than fit in the stepper still back up correctly.
+	key := strings.Repeat("x", 3*stepperParents)
+	for i := 1; i <= len(key); i++ {
+		rt.Put(key[:i], i)
+	}
+	s := rt.NewStepper()
+	if s.NextString(key) != len(key) {
+		t.Fatal("stepper did not advance over key")
+	}
+	cp := s.Copy()
+	if s.NextPrefix("xy") || s.Depth() != len(key) {
+		t.Fatal("failed NextPrefix should not move stepper")
+	}
+	for i := len(key); i > 0; i-- {
+		if val, _ := cp.Value(); val != i || cp.Key() != key[:i] {
+			t.Fatalf("expected value %d at depth %d, got %d", i, i, val)
+		}
+		if !cp.Back() {
+			t.Fatal("stepper did not back up")
+		}
+	}
+	if cp.Back() {
+		t.Fatal("expected stepper at root")
+	}
+}
+
+func TestStepperIntrospect(t *testing.T) {
+	rt := new(Tree[string])
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("tommy", "TOMMY")
+	rt.Put("torn", "TORN")
+
+	s := rt.NewStepper()
+	if s.Key() != "" {
+		t.Fatal("expected empty key at root")
+	}
+	if string(s.NextBytes()) != "t" || !s.IsPrefix() {
+		t.Fatal("expected next byte 't' at root")
+	}
+	var count int
+	for range s.Iter() {
+		count++
+	}
+	if count != 4 {
+		t.Fatalf("expected 4 items at root, got %d", count)
+	}
+
+	s.Next('t')
+	if s.Key() != "t" {
+		t.Fatalf("expected key \"t\", got %q", s.Key())
+	}
+	if string(s.NextBytes()) != "o" {
+		t.Fatalf("expected next byte 'o', got %q", s.NextBytes())
+	}
+	s.Next('o')
+	if string(s.NextBytes()) != "mr" {
+		t.Fatalf("expected next bytes \"mr\", got %q", s.NextBytes())
+	}
+	s.Next('m')
+	if s.Key() != "tom" || !s.IsPrefix() {
+		t.Fatal("expected key \"tom\" that is prefix")
+	}
+	var keys []string
+	for k := range s.Iter() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 3 || keys[0] != "tom" || keys[1] != "tomato" || keys[2] != "tommy" {
+		t.Fatal("wrong keys below \"tom\":", keys)
+	}
+
+	s.Next('m')
+	if s.Key() != "tomm" {
+		t.Fatalf("expected key \"tomm\", got %q", s.Key())
+	}
+	keys = keys[:0]
+	for k := range s.Iter() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 1 || keys[0] != "tommy" {
+		t.Fatal("wrong keys below \"tomm\":", keys)
+	}
+	s.Next('y')
+	if s.IsPrefix() || len(s.NextBytes()) != 0 {
+		t.Fatal("expected no next bytes at \"tommy\"")
+	}
+	s.Back()
+	s.Back()
+	s.Back()
+	if s.Key() != "to" {
+		t.Fatalf("expected key \"to\", got %q", s.Key())
+	}
+}
+
+func TestStepperValid(t *testing.T) {
+	rt := new(Tree[string])
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+
+	s := rt.NewStepper()
+	s.Next('t')
+	cp := s.Copy()
+	if !s.Valid() || !cp.Valid() {
+		t.Fatal("stepper should be valid")
+	}
+	rt.Delete("xyz")
+	if !s.Valid() {
+		t.Fatal("stepper should be valid after failed delete")
+	}
+	rt.Put("torn", "TORN")
+	if s.Valid() || cp.Valid() {
+		t.Fatal("stepper should be invalid after put")
+	}
+
+	// Unchecked mode does not panic.
+	s.Next('o')
+
+	s.Reset()
+	if !s.Valid() {
+		t.Fatal("stepper should be valid after reset")
+	}
+
+	rt.SetChecked(true)
+	s.Next('t')
+	rt.Delete("tom")
+	for name, fn := range map[string]func(){
+		"Next":  func() { s.Next('o') },
+		"Back":  func() { s.Back() },
+		"Item":  func() { s.Item() },
+		"Value": func() { s.Value() },
+		"Copy":  func() { s.Copy() },
+		"Key":   func() { s.Key() },
+		"Iter": func() {
+			for range s.Iter() {
+			}
+		},
+	} {
+		if !panics(fn) {
+			t.Errorf("expected %s to panic on invalid stepper", name)
+		}
+	}
+	s.Reset()
+	if panics(func() { s.Next('t') }) {
+		t.Fatal("should not panic after reset")
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
+
+func TestStepperNextString(t *testing.T) {
+	rt := new(Tree[string])
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("torn", "TORN")
+	rt.Put("tornado", "TORNADO")
+
+	s := rt.NewStepper()
+	if n := s.NextString("tomatoes"); n != 6 {
+		t.Fatalf("expected 6 bytes consumed, got %d", n)
+	}
+	if val, _ := s.Value(); val != "TOMATO" || s.Depth() != 6 {
+		t.Fatal("expected stepper at TOMATO")
+	}
+
+	s.Reset()
+	if n := s.NextString("tomx"); n != 3 {
+		t.Fatalf("expected 3 bytes consumed, got %d", n)
+	}
+	if val, _ := s.Value(); val != "TOM" {
+		t.Fatal("expected stepper at TOM")
+	}
+	if n := s.NextString("at"); n != 2 || s.Key() != "tomat" {
+		t.Fatal("expected stepper at \"tomat\", got", s.Key())
+	}
+
+	// Mismatch within a node prefix.
+	s.Reset()
+	if n := s.NextString("tornadx"); n != 6 || s.Key() != "tornad" {
+		t.Fatalf("expected stepper at \"tornad\" after %d bytes, got %q", n, s.Key())
+	}
+	for s.Back() {
+	}
+	if n := s.NextString("torn"); n != 4 {
+		t.Fatalf("expected 4 bytes consumed, got %d", n)
+	}
+	if val, _ := s.Value(); val != "TORN" {
+		t.Fatal("expected stepper at TORN")
+	}
+	if n := s.NextString(""); n != 0 {
+		t.Fatal("expected nothing consumed for empty string")
+	}
+
+	s.Reset()
+	if s.NextPrefix("tomx") {
+		t.Fatal("\"tomx\" should not have advanced stepper")
+	}
+	if s.Depth() != 0 || s.Key() != "" || s.Back() {
+		t.Fatal("stepper should not have moved")
+	}
+	if !s.NextPrefix("to") || !s.NextPrefix("rnad") {
+		t.Fatal("\"tornad\" should have advanced stepper")
+	}
+	if s.NextPrefix("ox") {
+		t.Fatal("\"ox\" should not have advanced stepper")
+	}
+	if s.Key() != "tornad" || !s.Next('o') {
+		t.Fatal("expected stepper at \"tornad\"")
+	}
+	if val, _ := s.Value(); val != "TORNADO" {
+		t.Fatal("expected stepper at TORNADO")
+	}
+}
@@ -0,0 +1,336 @@
+package radixtree
+
+import (
+	"slices"
+	"strings"
+)
+
+// Clone returns a copy of the tree. The node structure is copied directly, so
+// no keys are split or re-inserted. Values are copied by assignment; use
+// CloneWith to deep-copy values.
+func (t *Tree[T]) Clone() *Tree[T] {
+	return t.CloneWith(nil)
+}
+
+// CloneWith returns a copy of the tree, calling copyFn to copy each value. If
+// copyFn is nil, then values are copied by assignment.
+func (t *Tree[T]) CloneWith(copyFn func(T) T) *Tree[T] {
+	root, _ := t.root.clone(copyFn, 0)
+	return &Tree[T]{
+		root: *root,
+		size: t.size,
+	}
+}
+
+// Subtree returns a new tree containing a copy of all values whose keys are
+// prefixed by the given prefix. The matching node structure is copied
+// directly, so no keys are split or re-inserted. If trim is true, then the
+// prefix is removed from the keys in the new tree.
+func (t *Tree[T]) Subtree(prefix string, trim bool) *Tree[T] {
+	sub := new(Tree[T])
+	node, rem := t.locate(prefix)
+	if node == nil {
+		return sub
+	}
+
+	// The label is the part of the key, from the root of the new tree, that
+	// leads to the copied node.
+	var trimLen int
+	var label string
+	if trim {
+		trimLen = len(prefix)
+		label = rem
+	} else {
+		label = prefix + rem
+	}
+
+	child, count := node.clone(nil, trimLen)
+	if label == "" {
+		child.prefix = ""
+		sub.root = *child
+	} else {
+		child.prefix = label[1:]
+		sub.root.addEdge(label[0], child)
+	}
+	sub.size = count
+	return sub
+}
+
+// locate finds the node at or below which all keys prefixed by the given
+// prefix are stored. If the prefix ends within the node's prefix, then the
+// unmatched remainder of the node's prefix is also returned. Returns nil if no
+// keys have the prefix.
+func (t *Tree[T]) locate(prefix string) (*radixNode[T], string) {
+	node := &t.root
+	for len(prefix) != 0 {
+		if node = node.getEdge(prefix[0]); node == nil {
+			return nil, ""
+		}
+
+		// Consume prefix.
+		prefix = prefix[1:]
+		if !strings.HasPrefix(prefix, node.prefix) {
+			if strings.HasPrefix(node.prefix, prefix) {
+				return node, node.prefix[len(prefix):]
+			}
+			return nil, ""
+		}
+		prefix = prefix[len(node.prefix):]
+	}
+	return node, ""
+}
+
+// clone recursively copies the node and its descendants, and returns the copy
+// along with the number of values copied. The first trim bytes are removed
+// from the key of each copied item.
+func (node *radixNode[T]) clone(copyFn func(T) T, trim int) (*radixNode[T], int) {
+	var count int
+	cp := &radixNode[T]{
+		prefix:  node.prefix,
+		radices: slices.Clone(node.radices),
+	}
+	if node.leaf != nil {
+		value := node.leaf.value
+		if copyFn != nil {
+			value = copyFn(value)
+		}
+		cp.leaf = &Item[T]{
+			key:   node.leaf.key[trim:],
+			value: value,
+		}
+		count++
+	}
+	if node.nodes != nil {
+		cp.nodes = make([]*radixNode[T], len(node.nodes))
+		for i, child := range node.nodes {
+			var n int
+			cp.nodes[i], n = child.clone(copyFn, trim)
+			count += n
+		}
+	}
+	return cp, count
+}
+
+// Detach removes all values whose key is prefixed by the given prefix, and
+// returns them as a new tree. The matching node structure is moved to the new
+// tree without copying. If trim is true, then the prefix is removed from the
+// keys in the new tree, so that its values can be moved to another prefix with
+// Graft. Otherwise, items retain their original keys. Returns an empty tree if
+// no keys have the prefix.
+//
+// Locating and unlinking the subtree is O(prefix-length). Each detached value
+// is then visited once, to maintain the size of each tree, so the total cost
+// is also proportional to the number of detached values. When trimming, each
+// value is stored in a new Item with the trimmed key, so that Items already
+// obtained from the tree keep their original keys. To move values to another
+// prefix within the same tree, use Rename, which stores each value in a new
+// Item only once.
+func (t *Tree[T]) Detach(prefix string, trim bool) *Tree[T] {
+	sub := new(Tree[T])
+	moved, rem := t.unlink(prefix)
+	if moved == nil {
+		return sub
+	}
+	label := prefix + rem
+	var count int
+	if trim {
+		label = rem
+		count = moved.rekey(len(prefix), "")
+	} else {
+		for range moved.iter() {
+			count++
+		}
+	}
+	if label == "" {
+		sub.root = *moved
+	} else {
+		moved.prefix = label[1:]
+		sub.root.addEdge(label[0], moved)
+	}
+	sub.size = count
+	t.size -= count
+	return sub
+}
+
+// Rename moves all values whose key is prefixed by oldPrefix, so that each is
+// stored at the key formed by replacing oldPrefix with newPrefix. Returns the
+// number of values moved.
+//
+// This is the same as Detach(oldPrefix, true) followed by Graft(newPrefix),
+// except that each value is stored in a new Item only once. If the tree has
+// no keys prefixed by newPrefix once the values are removed, then the node
+// structure is moved directly beneath newPrefix. Otherwise, each value is put
+// into the tree, replacing any existing value with the same key. Items already
+// obtained from the tree keep their original keys.
+func (t *Tree[T]) Rename(oldPrefix, newPrefix string) int {
+	moved, rem := t.unlink(oldPrefix)
+	if moved == nil {
+		return 0
+	}
+	var count int
+	if node, _ := t.locate(newPrefix); node != nil && (node.leaf != nil || len(node.radices) != 0) {
+		// Keys with the new prefix already exist, so merge values into the
+		// tree.
+		for key, value := range moved.iter() {
+			t.size--
+			t.Put(newPrefix+key[len(oldPrefix):], value)
+			count++
+		}
+		return count
+	}
+	count = moved.rekey(len(oldPrefix), newPrefix)
+	t.attach(newPrefix+rem, moved)
+	return count
+}
+
+// unlink removes the node structure holding all keys prefixed by the given
+// prefix from the tree, and returns it as a node with no prefix, along with
+// the remainder of the node prefix that follows the given prefix. The keys of
+// the items are not changed, and the size of the tree is not adjusted. Returns
+// nil if no keys have the prefix.
+func (t *Tree[T]) unlink(prefix string) (*radixNode[T], string) {
+	node := &t.root
+	var (
+		parents []*radixNode[T]
+		links   []byte
+		rem     string
+		key     = prefix
+	)
+	for len(key) != 0 {
+		parents = append(parents, node)
+
+		// Find edge for radix.
+		node = node.getEdge(key[0])
+		if node == nil {
+			return nil, ""
+		}
+		links = append(links, key[0])
+
+		// Consume prefix.
+		key = key[1:]
+		if !strings.HasPrefix(key, node.prefix) {
+			if strings.HasPrefix(node.prefix, key) {
+				// Prefix consumed, so it prefixes every key from node down.
+				rem = node.prefix[len(key):]
+				break
+			}
+			return nil, ""
+		}
+		key = key[len(node.prefix):]
+	}
+	if node.leaf == nil && len(node.radices) == 0 {
+		return nil, ""
+	}
+
+	moved := &radixNode[T]{
+		radices: node.radices,
+		nodes:   node.nodes,
+		leaf:    node.leaf,
+	}
+	t.gen++
+
+	node.radices = nil
+	node.nodes = nil
+	node.leaf = nil
+
+	// If node is leaf, remove from parent. If parent becomes leaf, repeat.
+	node = node.prune(parents, links)
+
+	// If node has become compressible, compress it.
+	if node != &t.root {
+		node.compress()
+	}
+
+	return moved, rem
+}
+
+// Graft moves all values from sub into the tree, storing each value at a key
+// formed by the given prefix followed by the value's key in sub. The items
+// from sub are replaced by new Items with the prefixed keys, and sub is left
+// empty.
+//
+// If the tree has no keys prefixed by prefix, then the node structure of sub
+// is attached directly beneath prefix. Otherwise, each value from sub is put
+// into the tree, replacing any existing value with the same key.
+//
+// If sub is the same tree as t, then Graft does nothing.
+func (t *Tree[T]) Graft(prefix string, sub *Tree[T]) {
+	if sub == t || sub.size == 0 {
+		return
+	}
+	graft := &radixNode[T]{
+		radices: sub.root.radices,
+		nodes:   sub.root.nodes,
+		leaf:    sub.root.leaf,
+	}
+	count := sub.size
+	sub.root = radixNode[T]{}
+	sub.size = 0
+	sub.gen++
+
+	if node, _ := t.locate(prefix); node != nil && (node.leaf != nil || len(node.radices) != 0) {
+		// Keys with the prefix already exist, so merge values into the tree.
+		for key, value := range graft.iter() {
+			t.Put(prefix+key, value)
+		}
+		return
+	}
+	if prefix != "" {
+		graft.rekey(0, prefix)
+	}
+	t.attach(prefix, graft)
+	t.size += count
+	t.gen++
+}
+
+// attach adds the child node to the tree at the given key. There must not be
+// any existing keys prefixed by the key.
+func (t *Tree[T]) attach(key string, child *radixNode[T]) {
+	var p int
+	node := &t.root
+
+	for i := 0; i < len(key); i++ {
+		radix := key[i]
+		if p < len(node.prefix) {
+			if radix == node.prefix[p] {
+				p++
+				continue
+			}
+		} else if next := node.getEdge(radix); next != nil {
+			node = next
+			p = 0
+			continue
+		}
+		// If key partially matches node's prefix, then need to split node.
+		if p < len(node.prefix) {
+			node.split(p)
+		}
+		child.prefix = key[i+1:]
+		node.addEdge(radix, child)
+		child.compress()
+		return
+	}
+	// Key consumed without finding an unmatched edge, so the node is the
+	// empty root of an empty tree.
+	node.radices = child.radices
+	node.nodes = child.nodes
+	node.leaf = child.leaf
+}
+
+// rekey replaces each item in the node's subtree with a new item whose key
+// has the first n bytes replaced by prefix, and returns the number of items.
+// The original items are not modified, since they may be held by callers.
+func (node *radixNode[T]) rekey(n int, prefix string) int {
+	var count int
+	if node.leaf != nil {
+		node.leaf = &Item[T]{
+			key:   prefix + node.leaf.key[n:],
+			value: node.leaf.value,
+		}
+		count++
+	}
+	for _, child := range node.nodes {
+		count += child.rekey(n, prefix)
+	}
+	return count
+}
@@ -0,0 +1,431 @@
+package radixtree
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	rt := New[[]int]()
+	rt.Put("tom", []int{1})
+	rt.Put("tomato", []int{2})
+	rt.Put("torn", []int{3})
+	rt.Put("", []int{4})
+
+	cp := rt.Clone()
+	if cp.Len() != rt.Len() {
+		t.Fatalf("expected clone size %d, got %d", rt.Len(), cp.Len())
+	}
+	if dump(cp) != dump(rt) {
+		t.Log(dump(rt))
+		t.Log(dump(cp))
+		t.Fatal("clone structure differs from original")
+	}
+
+	// Modifying the clone must not affect the original.
+	cp.Put("tornado", []int{5})
+	cp.Delete("tom")
+	if _, ok := rt.Get("tornado"); ok {
+		t.Fatal("original should not have \"tornado\"")
+	}
+	if _, ok := rt.Get("tom"); !ok {
+		t.Fatal("original should still have \"tom\"")
+	}
+
+	// Values are shared by a shallow clone.
+	cp = rt.Clone()
+	val, _ := cp.Get("torn")
+	val[0] = 33
+	if val, _ = rt.Get("torn"); val[0] != 33 {
+		t.Fatal("expected value to be shared with shallow clone")
+	}
+
+	cp = rt.CloneWith(func(v []int) []int {
+		return append([]int(nil), v...)
+	})
+	val, _ = cp.Get("torn")
+	val[0] = 3
+	if val, _ = rt.Get("torn"); val[0] != 33 {
+		t.Fatal("expected value to be copied by CloneWith")
+	}
+
+	cp = New[[]int]().Clone()
+	if cp.Len() != 0 {
+		t.Fatal("expected empty clone")
+	}
+}
+
+func TestSubtree(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("tommy", "TOMMY")
+	rt.Put("torn", "TORN")
+	rt.Put("tornado", "TORNADO")
+
+	// (root) t-> ("o", _) m-> ("", TOM) a-> ("to", TOMATO)
+	//                                   m-> ("y", TOMMY)
+	//                     r-> ("n", TORN) a-> ("do", TORNADO)
+
+	sub := rt.Subtree("tom", false)
+	t.Log(dump(sub))
+	if sub.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", sub.Len())
+	}
+	for _, k := range []string{"tom", "tomato", "tommy"} {
+		if _, ok := sub.Get(k); !ok {
+			t.Errorf("missing %q in subtree", k)
+		}
+	}
+	if _, ok := sub.Get("torn"); ok {
+		t.Error("subtree should not have \"torn\"")
+	}
+
+	sub = rt.Subtree("tom", true)
+	t.Log(dump(sub))
+	if sub.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", sub.Len())
+	}
+	var keys []string
+	for k := range sub.Iter() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 3 || keys[0] != "" || keys[1] != "ato" || keys[2] != "my" {
+		t.Fatal("wrong keys in trimmed subtree:", keys)
+	}
+
+	// Prefix ends within a node's prefix.
+	sub = rt.Subtree("tornad", true)
+	t.Log(dump(sub))
+	if val, ok := sub.Get("o"); !ok || val != "TORNADO" {
+		t.Fatal("expected TORNADO at \"o\"")
+	}
+	sub = rt.Subtree("torna", false)
+	if val, ok := sub.Get("tornado"); !ok || val != "TORNADO" {
+		t.Fatal("expected TORNADO at \"tornado\"")
+	}
+	if sub.Len() != 1 {
+		t.Fatalf("expected 1 item, got %d", sub.Len())
+	}
+
+	// Subtree must be usable as a normal tree.
+	sub.Put("tornados", "TORNADOS")
+	sub.Put("tor", "TOR")
+	if sub.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", sub.Len())
+	}
+	if !sub.Delete("tornado") {
+		t.Fatal("failed to delete from subtree")
+	}
+	if _, ok := rt.Get("tornado"); !ok {
+		t.Fatal("deleting from subtree modified original")
+	}
+
+	sub = rt.Subtree("", false)
+	if dump(sub) != dump(rt) {
+		t.Fatal("subtree at empty prefix should equal original")
+	}
+
+	sub = rt.Subtree("tox", false)
+	if sub.Len() != 0 {
+		t.Fatal("expected empty subtree")
+	}
+}
+
+func TestDetach(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tom", "TOM")
+	rt.Put("tomato", "TOMATO")
+	rt.Put("tommy", "TOMMY")
+	rt.Put("torn", "TORN")
+	rt.Put("tornado", "TORNADO")
+
+	sub := rt.Detach("tox", false)
+	if sub.Len() != 0 {
+		t.Fatal("expected empty tree")
+	}
+
+	sub = rt.Detach("tom", false)
+	t.Log(dump(rt))
+	t.Log(dump(sub))
+	if sub.Len() != 3 {
+		t.Fatalf("expected 3 detached items, got %d", sub.Len())
+	}
+	if rt.Len() != 2 {
+		t.Fatalf("expected 2 remaining items, got %d", rt.Len())
+	}
+	for _, k := range []string{"tom", "tomato", "tommy"} {
+		if _, ok := sub.Get(k); !ok {
+			t.Errorf("missing %q in detached tree", k)
+		}
+		if _, ok := rt.Get(k); ok {
+			t.Errorf("%q should have been detached", k)
+		}
+	}
+
+	// Remaining node should be compressed.
+	node := rt.root.getEdge('t')
+	if node.prefix != "orn" {
+		t.Fatal("expected compressed prefix \"orn\", got", node.prefix)
+	}
+
+	// Prefix ends within a node's prefix.
+	sub = rt.Detach("tor", false)
+	if sub.Len() != 2 || rt.Len() != 0 {
+		t.Fatal("expected all items detached")
+	}
+	if val, ok := sub.Get("tornado"); !ok || val != "TORNADO" {
+		t.Fatal("expected TORNADO at \"tornado\"")
+	}
+	if len(rt.root.radices) != 0 {
+		t.Fatal("expected empty root")
+	}
+
+	sub = sub.Detach("", false)
+	if sub.Len() != 2 {
+		t.Fatal("expected all items detached")
+	}
+}
+
+func TestGraft(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tenantA/x", "AX")
+	rt.Put("tenantA/y", "AY")
+	rt.Put("tenantC/z", "CZ")
+
+	sub := rt.Detach("tenantA/", false)
+	rt.Graft("archive/", sub)
+	t.Log(dump(rt))
+	if sub.Len() != 0 {
+		t.Fatal("expected grafted tree to be empty")
+	}
+	if rt.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", rt.Len())
+	}
+	if val, ok := rt.Get("archive/tenantA/x"); !ok || val != "AX" {
+		t.Fatal("expected AX at \"archive/tenantA/x\"")
+	}
+	var keys []string
+	for k := range rt.IterAt("archive/") {
+		keys = append(keys, k)
+	}
+	if len(keys) != 2 || keys[0] != "archive/tenantA/x" || keys[1] != "archive/tenantA/y" {
+		t.Fatal("wrong keys after graft:", keys)
+	}
+
+	// Graft at a prefix that splits an existing node.
+	sub = New[string]()
+	sub.Put("", "B")
+	sub.Put("/w", "BW")
+	rt.Graft("tenantB", sub)
+	t.Log(dump(rt))
+	if rt.Len() != 5 {
+		t.Fatalf("expected 5 items, got %d", rt.Len())
+	}
+	if val, ok := rt.Get("tenantB"); !ok || val != "B" {
+		t.Fatal("expected B at \"tenantB\"")
+	}
+	if val, ok := rt.Get("tenantB/w"); !ok || val != "BW" {
+		t.Fatal("expected BW at \"tenantB/w\"")
+	}
+	if val, ok := rt.Get("tenantC/z"); !ok || val != "CZ" {
+		t.Fatal("expected CZ at \"tenantC/z\"")
+	}
+
+	// Graft where keys with the prefix already exist.
+	sub = New[string]()
+	sub.Put("/w", "BW2")
+	sub.Put("/v", "BV")
+	rt.Graft("tenantB", sub)
+	if rt.Len() != 6 {
+		t.Fatalf("expected 6 items, got %d", rt.Len())
+	}
+	if val, _ := rt.Get("tenantB/w"); val != "BW2" {
+		t.Fatal("expected BW2 at \"tenantB/w\"")
+	}
+
+	// Graft into empty tree at empty prefix.
+	empty := New[string]()
+	empty.Graft("", rt.Clone())
+	if dump(empty) != dump(rt) || empty.Len() != rt.Len() {
+		t.Fatal("expected graft into empty tree to equal original")
+	}
+
+	rt.Graft("x", rt)
+	if rt.Len() != 6 {
+		t.Fatal("graft of tree onto itself should do nothing")
+	}
+}
+
+func TestDetachTrim(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tenantA/x", "AX")
+	rt.Put("tenantA/y", "AY")
+	rt.Put("tenantA/y/z", "AYZ")
+	rt.Put("tenantC/z", "CZ")
+
+	// Rename tenantA to tenantB.
+	sub := rt.Detach("tenantA/", true)
+	if sub.Len() != 3 || rt.Len() != 1 {
+		t.Fatalf("expected 3 detached and 1 remaining, got %d and %d", sub.Len(), rt.Len())
+	}
+	if val, ok := sub.Get("y/z"); !ok || val != "AYZ" {
+		t.Fatal("expected AYZ at trimmed key \"y/z\"")
+	}
+	rt.Graft("tenantB/", sub)
+
+	var keys []string
+	for k := range rt.Iter() {
+		keys = append(keys, k)
+	}
+	want := []string{"tenantB/x", "tenantB/y", "tenantB/y/z", "tenantC/z"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("expected keys %q, got %q", want, keys)
+	}
+	for key, val := range map[string]string{"tenantB/x": "AX", "tenantB/y/z": "AYZ"} {
+		if v, ok := rt.Get(key); !ok || v != val {
+			t.Fatalf("expected %s at %q", val, key)
+		}
+	}
+
+	// Prefix ends within a node's prefix, leaving the rest of the node prefix
+	// in the trimmed keys.
+	sub = rt.Detach("tenantB/y/", true)
+	if sub.Len() != 1 {
+		t.Fatalf("expected 1 detached item, got %d", sub.Len())
+	}
+	if val, ok := sub.Get("z"); !ok || val != "AYZ" {
+		t.Fatal("expected AYZ at \"z\"")
+	}
+	for k, v := range sub.Iter() {
+		if k != "z" || v != "AYZ" {
+			t.Fatalf("unexpected item %q: %q", k, v)
+		}
+	}
+
+	// Trimming the whole key leaves the value at the empty key.
+	sub = rt.Detach("tenantC/z", true)
+	if val, ok := sub.Get(""); !ok || val != "CZ" {
+		t.Fatal("expected CZ at empty key")
+	}
+}
+
+func TestDetachGraftKeepItems(t *testing.T) {
+	rt := New[int]()
+	rt.Put("tenant1/foo", 1)
+	rt.Put("tenant1/bar", 2)
+	rt.Put("other", 3)
+
+	s := rt.NewStepper()
+	s.NextString("tenant1/foo")
+	item := s.Item()
+	if item == nil {
+		t.Fatal("expected item at tenant1/foo")
+	}
+	m := rt.NewMatcher()
+
+	sub := rt.Detach("tenant1/", true)
+	if item.Key() != "tenant1/foo" {
+		t.Fatalf("detach changed key of held item to %q", item.Key())
+	}
+	for off, it := range m.MatchString("xx tenant1/foo") {
+		if off != 3 || it.Key() != "tenant1/foo" {
+			t.Fatalf("expected match of tenant1/foo at 3, got %q at %d", it.Key(), off)
+		}
+	}
+
+	s = sub.NewStepper()
+	s.NextString("foo")
+	item = s.Item()
+	rt.Graft("tenant2/", sub)
+	if item.Key() != "foo" {
+		t.Fatalf("graft changed key of held item to %q", item.Key())
+	}
+	if val, ok := rt.Get("tenant2/foo"); !ok || val != 1 {
+		t.Fatal("expected 1 at tenant2/foo")
+	}
+}
+
+func TestRename(t *testing.T) {
+	rt := New[string]()
+	rt.Put("tenantA/x", "AX")
+	rt.Put("tenantA/y", "AY")
+	rt.Put("tenantA/y/z", "AYZ")
+	rt.Put("tenantC/z", "CZ")
+
+	s := rt.NewStepper()
+	s.NextString("tenantA/x")
+	item := s.Item()
+
+	if n := rt.Rename("tenantA/", "tenantB/"); n != 3 {
+		t.Fatalf("expected 3 values moved, got %d", n)
+	}
+	if item.Key() != "tenantA/x" {
+		t.Fatalf("rename changed key of held item to %q", item.Key())
+	}
+	var keys []string
+	for k := range rt.Iter() {
+		keys = append(keys, k)
+	}
+	want := []string{"tenantB/x", "tenantB/y", "tenantB/y/z", "tenantC/z"}
+	if !slices.Equal(keys, want) || rt.Len() != 4 {
+		t.Fatalf("expected keys %q, got %q", want, keys)
+	}
+	for _, k := range keys {
+		s.Reset()
+		if s.NextString(k) != len(k) || s.Item().Key() != k {
+			t.Fatalf("item at %q has wrong key", k)
+		}
+	}
+
+	// Prefix ending within a node's prefix, renamed to a prefix where keys
+	// already exist.
+	rt.Put("tenantC/zz", "CZZ")
+	if n := rt.Rename("tenantB/y/", "tenantC/"); n != 1 {
+		t.Fatalf("expected 1 value moved, got %d", n)
+	}
+	if val, ok := rt.Get("tenantC/z"); !ok || val != "AYZ" || rt.Len() != 4 {
+		t.Fatal("expected AYZ to replace CZ at tenantC/z")
+	}
+	if n := rt.Rename("none/", "tenantD/"); n != 0 {
+		t.Fatalf("expected nothing moved, got %d", n)
+	}
+
+	// Rename to a longer prefix of the same keys.
+	if n := rt.Rename("tenantC/", "tenantC/old/"); n != 2 {
+		t.Fatalf("expected 2 values moved, got %d", n)
+	}
+	keys = keys[:0]
+	for k := range rt.Iter() {
+		keys = append(keys, k)
+	}
+	want = []string{"tenantB/x", "tenantB/y", "tenantC/old/z", "tenantC/old/zz"}
+	if !slices.Equal(keys, want) || rt.Len() != 4 {
+		t.Fatalf("expected keys %q, got %q", want, keys)
+	}
+}
+
+func TestRenameAllocs(t *testing.T) {
+	const n = 100
+	rt := New[int]()
+	for i := range n {
+		rt.Put("tenantA/"+strconv.Itoa(i), i)
+	}
+	rt.Put("other", -1)
+
+	// Each rename stores each value in one new Item, with one new key.
+	allocs := testing.AllocsPerRun(10, func() {
+		rt.Rename("tenantA/", "tenantB/")
+		rt.Rename("tenantB/", "tenantA/")
+	})
+	if allocs > 4*n+20 {
+		t.Fatalf("expected about %d allocations, got %v", 4*n, allocs)
+	}
+	if rt.Len() != n+1 {
+		t.Fatalf("expected %d values, got %d", n+1, rt.Len())
+	}
+	if val, ok := rt.Get("tenantA/42"); !ok || val != 42 {
+		t.Fatal("expected 42 at tenantA/42")
+	}
+}
@@ -0,0 +1,69 @@
+package radixtree
+
+import (
+	"iter"
+)
+
+// UnknownPolicy determines how Tokenize handles input that does not begin
+// with any key in the tree.
+type UnknownPolicy int
+
+const (
+	// SkipUnknown discards input bytes that do not begin any key.
+	SkipUnknown UnknownPolicy = iota
+	// EmitUnknown yields each run of input bytes that do not begin any key as
+	// a single token, with a nil Item.
+	EmitUnknown
+)
+
+// Tokenize splits the input into tokens that are keys in the tree, using
+// greedy longest-match. Starting at the beginning of the input, the longest
+// key that prefixes the remaining input is yielded with the Item stored for
+// the key, and tokenizing continues after it. Bytes that do not begin any key
+// are handled according to the unknown policy, and unknown tokens are yielded
+// with a nil Item. The empty key is never matched.
+//
+// Yielded tokens are substrings of the input, so no memory is allocated for
+// them.
+func (t *Tree[T]) Tokenize(input string, unknown UnknownPolicy) iter.Seq2[string, *Item[T]] {
+	return func(yield func(string, *Item[T]) bool) {
+		yield = checkedYield(t, yield)
+		s := t.NewStepper()
+		unknownStart := -1
+		for pos := 0; pos < len(input); {
+			// Step as far as possible, remembering the last key found.
+			var (
+				end   int
+				found *Item[T]
+			)
+			for i := pos; i < len(input) && s.Next(input[i]); i++ {
+				if item := s.Item(); item != nil {
+					end = i + 1
+					found = item
+				}
+			}
+			s.Reset()
+
+			if end == 0 {
+				if unknownStart == -1 {
+					unknownStart = pos
+				}
+				pos++
+				continue
+			}
+			if unknownStart != -1 {
+				if unknown == EmitUnknown && !yield(input[unknownStart:pos], nil) {
+					return
+				}
+				unknownStart = -1
+			}
+			if !yield(input[pos:end], found) {
+				return
+			}
+			pos = end
+		}
+		if unknownStart != -1 && unknown == EmitUnknown {
+			yield(input[unknownStart:], nil)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	rt := New[int]()
+	for i, k := range []string{"", "the", "then", "there", "in", "inn", "a", "an", "and"} {
+		rt.Put(k, i)
+	}
+
+	// The key "the" has the zero value, which must be distinguishable from
+	// unknown tokens.
+	rt.Put("the", 0)
+
+	type token struct {
+		s     string
+		v     int
+		known bool
+	}
+	tests := []struct {
+		input   string
+		unknown UnknownPolicy
+		expect  []token
+	}{
+		{"thereinnand", SkipUnknown, []token{{"there", 3, true}, {"inn", 5, true}, {"and", 8, true}}},
+		{"theninandthe", SkipUnknown, []token{{"then", 2, true}, {"in", 4, true}, {"and", 8, true}, {"the", 0, true}}},
+		{"thx an", SkipUnknown, []token{{"an", 7, true}}},
+		{"thx an", EmitUnknown, []token{{"thx ", 0, false}, {"an", 7, true}}},
+		{"an, the!", EmitUnknown, []token{{"an", 7, true}, {", ", 0, false}, {"the", 0, true}, {"!", 0, false}}},
+		{"xyz", EmitUnknown, []token{{"xyz", 0, false}}},
+		{"xyz", SkipUnknown, nil},
+		{"", EmitUnknown, nil},
+		{"then", EmitUnknown, []token{{"then", 2, true}}},
+		// Longest key "ther" is not stored, so fall back to "the".
+		{"therx", EmitUnknown, []token{{"the", 0, true}, {"rx", 0, false}}},
+	}
+	for _, tc := range tests {
+		var found []token
+		for s, item := range rt.Tokenize(tc.input, tc.unknown) {
+			if item == nil {
+				found = append(found, token{s, 0, false})
+				continue
+			}
+			if item.Key() != s {
+				t.Fatalf("token %q yielded with item for %q", s, item.Key())
+			}
+			found = append(found, token{s, item.Value(), true})
+		}
+		if !slices.Equal(found, tc.expect) {
+			t.Errorf("input %q: expected %v, got %v", tc.input, tc.expect, found)
+		}
+	}
+
+	var count int
+	for range rt.Tokenize("thethethe", SkipUnknown) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+}
@@ -0,0 +1,153 @@
+package radixtree
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// TopicTree stores publish/subscribe topic filters, which may contain
+// wildcards, and finds the filters that match a published topic. Filters and
+// topics use MQTT syntax, with levels separated by '/':
+//
+//	sensors/+/temp   '+' matches exactly one level
+//	alerts/#         '#' matches the parent level and any number of levels
+//
+// A wildcard must occupy a whole level, and '#' must be the last level. As in
+// MQTT, a wildcard at the first level does not match a topic that begins with
+// '$', so that system topics are only matched by filters that name them.
+//
+// Filters are stored in a Tree, so the literal parts of filters share storage
+// for common prefixes, and matching only branches at wildcard levels.
+type TopicTree[T any] struct {
+	tree Tree[T]
+}
+
+const (
+	topicSep    = '/'
+	topicSingle = '+'
+	topicMulti  = '#'
+)
+
+// NewTopicTree creates a new topic filter tree.
+func NewTopicTree[T any]() *TopicTree[T] {
+	return new(TopicTree[T])
+}
+
+// Len returns the number of filters stored in the tree.
+func (tt *TopicTree[T]) Len() int {
+	return tt.tree.Len()
+}
+
+// Add stores the value for the topic filter, replacing any existing value for
+// the filter. Returns an error if the filter is empty or uses a wildcard
+// incorrectly.
+func (tt *TopicTree[T]) Add(filter string, value T) error {
+	if err := checkTopicFilter(filter); err != nil {
+		return err
+	}
+	tt.tree.Put(filter, value)
+	return nil
+}
+
+// Get returns the value stored for exactly the given filter. Returns false if
+// there is no value present for the filter.
+func (tt *TopicTree[T]) Get(filter string) (T, bool) {
+	return tt.tree.Get(filter)
+}
+
+// Remove removes the topic filter. Returns true if the filter was stored.
+func (tt *TopicTree[T]) Remove(filter string) bool {
+	return tt.tree.Delete(filter)
+}
+
+// Iter visits all filters in the tree, yielding the filter and value of each.
+//
+// The tree is traversed in lexical order, making the output deterministic.
+func (tt *TopicTree[T]) Iter() iter.Seq2[string, T] {
+	return tt.tree.Iter()
+}
+
+// Match visits every filter that matches the published topic, yielding the
+// filter and value of each.
+func (tt *TopicTree[T]) Match(topic string) iter.Seq2[string, T] {
+	return func(yield func(string, T) bool) {
+		yield = checkedYield(&tt.tree, yield)
+		wild := len(topic) == 0 || topic[0] != '$'
+		matchTopic(nodePos[T]{node: &tt.tree.root}, topic, wild, yield)
+	}
+}
+
+// checkTopicFilter returns an error if the filter is not valid.
+func checkTopicFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("radixtree: empty topic filter")
+	}
+	for level := range strings.SplitSeq(filter, string(topicSep)) {
+		if len(level) > 1 && strings.ContainsAny(level, string([]byte{topicSingle, topicMulti})) {
+			return fmt.Errorf("radixtree: wildcard must occupy whole level in topic filter %q", filter)
+		}
+	}
+	if i := strings.IndexByte(filter, topicMulti); i != -1 && i != len(filter)-1 {
+		return fmt.Errorf("radixtree: %q must be last level in topic filter %q", topicMulti, filter)
+	}
+	return nil
+}
+
+// matchTopic matches the remaining topic, which starts at the beginning of a
+// level, from the tree position. If wild is false, then wildcards do not match
+// this level. Returns false if iteration was stopped.
+func matchTopic[T any](pos nodePos[T], topic string, wild bool, yield func(string, T) bool) bool {
+	level, rest, more := strings.Cut(topic, string(topicSep))
+
+	if wild {
+		if next, ok := pos.next(topicMulti); ok {
+			if item := next.item(); item != nil && !yield(item.key, item.value) {
+				return false
+			}
+		}
+		if next, ok := pos.next(topicSingle); ok {
+			if !matchTopicLevel(next, rest, more, yield) {
+				return false
+			}
+		}
+	}
+
+	// A topic level that is a wildcard character only matches as a wildcard,
+	// so that a filter is not matched twice.
+	if len(level) == 1 && (level[0] == topicSingle || level[0] == topicMulti) {
+		return true
+	}
+	for i := 0; i < len(level); i++ {
+		var ok bool
+		if pos, ok = pos.next(level[i]); !ok {
+			return true
+		}
+	}
+	return matchTopicLevel(pos, rest, more, yield)
+}
+
+// matchTopicLevel continues matching after a level of the topic has been
+// matched. If there are more levels, then they are matched. Otherwise, the
+// filter at the position is yielded, along with any filter that continues with
+// a multi-level wildcard, since that also matches its parent level.
+func matchTopicLevel[T any](pos nodePos[T], rest string, more bool, yield func(string, T) bool) bool {
+	next, ok := pos.next(topicSep)
+	if more {
+		if !ok {
+			return true
+		}
+		return matchTopic(next, rest, true, yield)
+	}
+	if item := pos.item(); item != nil && !yield(item.key, item.value) {
+		return false
+	}
+	if ok {
+		if next, ok = next.next(topicMulti); ok {
+			if item := next.item(); item != nil && !yield(item.key, item.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopicTree(t *testing.T) {
+	tt := NewTopicTree[int]()
+	filters := []string{
+		"sensors/+/temp",
+		"sensors/42/temp",
+		"sensors/42/+",
+		"sensors/#",
+		"sensors/+/+/raw",
+		"alerts/#",
+		"alerts",
+		"+/42/#",
+		"#",
+		"+",
+		"$SYS/#",
+		"/leading",
+		"+/leading",
+	}
+	for i, f := range filters {
+		if err := tt.Add(f, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tt.Len() != len(filters) {
+		t.Fatalf("expected %d filters, got %d", len(filters), tt.Len())
+	}
+
+	tests := []struct {
+		topic  string
+		expect []string
+	}{
+		{"sensors/42/temp", []string{"#", "+/42/#", "sensors/#", "sensors/+/temp", "sensors/42/+", "sensors/42/temp"}},
+		{"sensors/7/temp", []string{"#", "sensors/#", "sensors/+/temp"}},
+		{"sensors/7/x/raw", []string{"#", "sensors/#", "sensors/+/+/raw"}},
+		{"sensors", []string{"#", "+", "sensors/#"}},
+		{"alerts", []string{"#", "+", "alerts", "alerts/#"}},
+		{"alerts/fire/now", []string{"#", "alerts/#"}},
+		{"x/42", []string{"#", "+/42/#"}},
+		{"$SYS/uptime", []string{"$SYS/#"}},
+		{"/leading", []string{"#", "+/leading", "/leading"}},
+		{"other/thing", []string{"#"}},
+	}
+	for _, tc := range tests {
+		var found []string
+		for f, v := range tt.Match(tc.topic) {
+			if filters[v] != f {
+				t.Fatalf("wrong value %d for filter %q", v, f)
+			}
+			found = append(found, f)
+		}
+		slices.Sort(found)
+		slices.Sort(tc.expect)
+		if !slices.Equal(found, tc.expect) {
+			t.Errorf("topic %q: expected %q, got %q", tc.topic, tc.expect, found)
+		}
+	}
+
+	if v, ok := tt.Get("sensors/#"); !ok || filters[v] != "sensors/#" {
+		t.Fatal("expected value for filter \"sensors/#\"")
+	}
+	if !tt.Remove("#") || tt.Remove("#") {
+		t.Fatal("expected filter \"#\" to be removed once")
+	}
+	var count int
+	for range tt.Match("other/thing") {
+		count++
+	}
+	if count != 0 {
+		t.Fatal("expected no match after removing \"#\"")
+	}
+	for range tt.Match("sensors/42/temp") {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatal("iteration did not stop")
+	}
+	count = 0
+	for range tt.Iter() {
+		count++
+	}
+	if count != tt.Len() {
+		t.Fatal("expected to iterate all filters")
+	}
+
+	for _, bad := range []string{"", "a/#/b", "a/b#", "a+/b", "#/"} {
+		if err := tt.Add(bad, 0); err == nil {
+			t.Errorf("expected error adding filter %q", bad)
+		}
+	}
+}
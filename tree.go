@@ -9,6 +9,10 @@ import (
 type Tree[T any] struct {
 	root radixNode[T]
 	size int
+	// gen is incremented by every modification of the tree, so that steppers
+	// and iterators can detect that they have been invalidated.
+	gen     uint64
+	checked bool
 }
 
 // New creates a new bytes-based radix tree
@@ -49,6 +53,33 @@ func (t *Tree[T]) Len() int {
 	return t.size
 }
 
+// SetChecked enables or disables checked mode. In checked mode, using a
+// Stepper after the tree has been modified, or modifying the tree while an
+// iterator is running, causes a panic. This detects misuse that would
+// otherwise silently return wrong results, at the cost of a check on each
+// Stepper call and an allocation for each iteration.
+func (t *Tree[T]) SetChecked(checked bool) {
+	t.checked = checked
+}
+
+// checkedYield wraps yield, when the tree is in checked mode, with a function
+// that panics if the tree is modified by the caller of the iterator.
+func checkedYield[T, K, V any](t *Tree[T], yield func(K, V) bool) func(K, V) bool {
+	if !t.checked {
+		return yield
+	}
+	gen := t.gen
+	return func(k K, v V) bool {
+		if !yield(k, v) {
+			return false
+		}
+		if t.gen != gen {
+			panic("radixtree: tree modified during iteration")
+		}
+		return true
+	}
+}
+
 // Get returns the value stored at the given key. Returns false if there is no
 // value present for the key.
 func (t *Tree[T]) Get(key string) (T, bool) {
@@ -80,6 +111,16 @@ func (t *Tree[T]) Get(key string) (T, bool) {
 // items. It returns true if it adds a new value, false if it replaces an
 // existing value.
 func (t *Tree[T]) Put(key string, value T) bool {
+	return t.put(key, &Item[T]{
+		key:   key,
+		value: value,
+	})
+}
+
+// put stores the item in the tree at the given key, replacing any existing
+// item. The item is stored as given, so trees that do not read keys from their
+// items may share one item between keys.
+func (t *Tree[T]) put(key string, leaf *Item[T]) bool {
 	var (
 		p            int
 		isNewValue   bool
@@ -105,10 +146,7 @@ func (t *Tree[T]) Put(key string, value T) bool {
 		// key data, so add a child that has a prefix of the unmatched key data
 		// and set its value to the new value.
 		newChild := &radixNode[T]{
-			leaf: &Item[T]{
-				key:   key,
-				value: value,
-			},
+			leaf: leaf,
 		}
 		if i < len(key)-1 {
 			newChild.prefix = key[i+1:]
@@ -137,11 +175,9 @@ func (t *Tree[T]) Put(key string, value T) bool {
 			isNewValue = true
 			t.size++
 		}
-		node.leaf = &Item[T]{
-			key:   key,
-			value: value,
-		}
+		node.leaf = leaf
 	}
+	t.gen++
 
 	return isNewValue
 }
@@ -183,6 +219,7 @@ func (t *Tree[T]) Delete(key string) bool {
 	// delete the node value, indicate that value was deleted.
 	node.leaf = nil
 	t.size--
+	t.gen++
 
 	// If node is leaf, remove from parent. If parent becomes leaf, repeat.
 	node = node.prune(parents, links)
@@ -225,6 +262,10 @@ func (t *Tree[T]) DeletePrefix(prefix string) bool {
 		}
 		prefix = prefix[len(node.prefix):]
 	}
+	if node.leaf == nil && len(node.radices) == 0 {
+		// Prefix is the empty root of an empty tree.
+		return false
+	}
 
 	if node.radices != nil {
 		var count int
@@ -238,6 +279,7 @@ func (t *Tree[T]) DeletePrefix(prefix string) bool {
 		t.size--
 	}
 	node.leaf = nil
+	t.gen++
 
 	// If node is leaf, remove from parent. If parent becomes leaf, repeat.
 	node = node.prune(parents, links)
@@ -254,7 +296,9 @@ func (t *Tree[T]) DeletePrefix(prefix string) bool {
 //
 // The tree is traversed in lexical order, making the output deterministic.
 func (t *Tree[T]) Iter() iter.Seq2[string, T] {
-	return t.root.iter()
+	return func(yield func(string, T) bool) {
+		t.root.walk(checkedYield(t, yield))
+	}
 }
 
 // IterAt visits all nodes whose keys match or are prefixed by the specified
@@ -282,7 +326,7 @@ func (t *Tree[T]) IterAt(key string) iter.Seq2[string, T] {
 			key = key[len(node.prefix):]
 		}
 		// Iterate the subtree.
-		node.walk(yield)
+		node.walk(checkedYield(t, yield))
 	}
 }
 
@@ -310,6 +354,7 @@ func (node *radixNode[T]) walk(yield func(string, T) bool) bool {
 // The tree is traversed in lexical order, making the output deterministic.
 func (t *Tree[T]) IterPath(key string) iter.Seq2[string, T] {
 	return func(yield func(string, T) bool) {
+		yield = checkedYield(t, yield)
 		node := &t.root
 		for {
 			if node.leaf != nil && !yield(node.leaf.key, node.leaf.value) {
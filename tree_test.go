@@ -3,6 +3,7 @@ package radixtree
 import (
 	"errors"
 	"fmt"
+	"iter"
 	"strings"
 	"testing"
 )
@@ -1139,3 +1140,52 @@ func dump[T any](tree *Tree[T]) string {
 	})
 	return b.String()
 }
+
+func TestCheckedIter(t *testing.T) {
+	rt := New[int]()
+	for i, k := range []string{"a", "ab", "abc", "b"} {
+		rt.Put(k, i)
+	}
+
+	// Unchecked mode does not detect modification.
+	for k := range rt.Iter() {
+		if k == "ab" {
+			rt.Put("abcd", 9)
+		}
+	}
+
+	rt.SetChecked(true)
+	for range rt.Iter() {
+	}
+	iters := map[string]func() iter.Seq2[string, int]{
+		"Iter":          rt.Iter,
+		"IterAt":        func() iter.Seq2[string, int] { return rt.IterAt("a") },
+		"IterPath":      func() iter.Seq2[string, int] { return rt.IterPath("abc") },
+		"IterFuzzy":     func() iter.Seq2[string, int] { return rt.IterFuzzy("ab", 2) },
+		"IterGlob":      func() iter.Seq2[string, int] { return rt.IterGlob("a*") },
+		"NewStepper":    func() iter.Seq2[string, int] { return rt.NewStepper().Iter() },
+		"IterAutomaton": func() iter.Seq2[string, int] { return rt.IterAutomaton(allAutomaton{}) },
+	}
+	for name, seq := range iters {
+		var panicked bool
+		func() {
+			defer func() {
+				panicked = recover() != nil
+			}()
+			for k := range seq() {
+				rt.Put(k+"x", 0)
+			}
+		}()
+		if !panicked {
+			t.Errorf("expected %s to panic when tree modified", name)
+		}
+	}
+}
+
+// allAutomaton is an Automaton that accepts every key.
+type allAutomaton struct{}
+
+func (allAutomaton) Start() int         { return 0 }
+func (allAutomaton) Step(int, byte) int { return 0 }
+func (allAutomaton) IsMatch(int) bool   { return true }
+func (allAutomaton) CanMatch(int) bool  { return true }